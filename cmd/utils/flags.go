@@ -402,6 +402,12 @@ var (
 		Value:    ethconfig.Defaults.TxPool.PriceLimit,
 		Category: flags.TxPoolCategory,
 	}
+	TxPoolMiningGasFloorFlag = &cli.Uint64Flag{
+		Name:     "txpool.mininggasfloor",
+		Usage:    "Minimum gas limit to enforce for mining transactions (0 = disabled)",
+		Value:    ethconfig.Defaults.TxPool.MiningGasFloor,
+		Category: flags.TxPoolCategory,
+	}
 	TxPoolPriceBumpFlag = &cli.Uint64Flag{
 		Name:     "txpool.pricebump",
 		Usage:    "Price bump percentage to replace an already existing transaction",
@@ -1587,6 +1593,9 @@ func setTxPool(ctx *cli.Context, cfg *txpool.Config) {
 	if ctx.IsSet(TxPoolPriceBumpFlag.Name) {
 		cfg.PriceBump = ctx.Uint64(TxPoolPriceBumpFlag.Name)
 	}
+	if ctx.IsSet(TxPoolMiningGasFloorFlag.Name) {
+		cfg.MiningGasFloor = ctx.Uint64(TxPoolMiningGasFloorFlag.Name)
+	}
 	if ctx.IsSet(TxPoolAccountSlotsFlag.Name) {
 		cfg.AccountSlots = ctx.Uint64(TxPoolAccountSlotsFlag.Name)
 	}
@@ -460,8 +460,8 @@ func (beacon *Beacon) SetThreads(threads int) {
 }
 
 // Calculate offline mining reward base on block number
-func (beacon *Beacon) TransactionMiningSubsidy(config *params.ChainConfig, block *big.Int) *big.Int {
-	return beacon.ethone.TransactionMiningSubsidy(config, block)
+func (beacon *Beacon) TransactionMiningSubsidy(config *params.ChainConfig, block *big.Int, algorithm uint8) *big.Int {
+	return beacon.ethone.TransactionMiningSubsidy(config, block, algorithm)
 }
 
 // IsTTDReached checks if the TotalTerminalDifficulty has been surpassed on the `parentHash` block.
@@ -705,7 +705,7 @@ func calcDifficulty(snap *Snapshot, signer common.Address) *big.Int {
 }
 
 // Calculate offline mining reward base on block number
-func (c *Clique) TransactionMiningSubsidy(config *params.ChainConfig, block *big.Int) *big.Int {
+func (c *Clique) TransactionMiningSubsidy(config *params.ChainConfig, block *big.Int, algorithm uint8) *big.Int {
 	return big0
 }
 
@@ -124,8 +124,8 @@ type Engine interface {
 	CalcDifficulty(chain ChainHeaderReader, time uint64, parent *types.Header) *big.Int
 
 	// TransactionMiningSubsidy is the offline mining adjustment algorithm. It returns the mining subsidy
-	// base on block number.
-	TransactionMiningSubsidy(config *params.ChainConfig, block *big.Int) *big.Int
+	// base on block number and the mining algorithm of the transaction.
+	TransactionMiningSubsidy(config *params.ChainConfig, block *big.Int, algorithm uint8) *big.Int
 
 	// APIs returns the RPC APIs this consensus engine provides.
 	APIs(chain ChainHeaderReader) []rpc.API
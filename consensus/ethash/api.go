@@ -18,19 +18,36 @@ package ethash
 
 import (
 	"errors"
+	"fmt"
+	"math/big"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/consensus"
 	"github.com/ethereum/go-ethereum/core/types"
 )
 
-var errEthashStopped = errors.New("ethash stopped")
+// maxBenchmarkSeconds is the longest hashrate benchmark that can be requested,
+// as the benchmark keeps every mining thread busy while it runs.
+const maxBenchmarkSeconds = 60
+
+var (
+	errEthashStopped     = errors.New("ethash stopped")
+	errBenchmarkDuration = errors.New("invalid benchmark duration")
+)
 
 // API exposes ethash related methods for the RPC interface.
 type API struct {
 	ethash *Ethash
 }
 
+// ChainAPI exposes read-only ethash chain information, which is only made
+// available in the ethash namespace.
+type ChainAPI struct {
+	chain consensus.ChainHeaderReader
+}
+
 // GetWork returns a work package for external miner.
 //
 // The work package consists of 3 strings:
@@ -111,3 +128,126 @@ func (api *API) SubmitHashrate(rate hexutil.Uint64, id common.Hash) bool {
 func (api *API) GetHashrate() uint64 {
 	return uint64(api.ethash.Hashrate())
 }
+
+// MinerAPI exposes ethash methods affecting the local machine, which are only
+// made available in the miner namespace.
+type MinerAPI struct {
+	ethash *Ethash
+}
+
+// Benchmark runs the local hashing routine of the given mining algorithm for the
+// given number of seconds, at most maxBenchmarkSeconds, without sealing or
+// submitting anything, and returns the measured hashrate in hashes per second.
+func (api *MinerAPI) Benchmark(algorithm uint8, seconds uint64) (hexutil.Uint64, error) {
+	if seconds == 0 || seconds > maxBenchmarkSeconds {
+		return 0, fmt.Errorf("%w: have %d seconds, want 1 to %d", errBenchmarkDuration, seconds, maxBenchmarkSeconds)
+	}
+	rate, err := api.ethash.benchmark(algorithm, time.Duration(seconds)*time.Second)
+	if err != nil {
+		return 0, err
+	}
+	return hexutil.Uint64(rate), nil
+}
+
+// RewardSplit is the split of a block reward between the miner and the
+// foundation wallet.
+type RewardSplit struct {
+	Number            *hexutil.Big   `json:"number"`
+	MinerReward       *hexutil.Big   `json:"minerReward"`
+	FoundationReward  *hexutil.Big   `json:"foundationReward"`
+	FoundationPercent hexutil.Uint64 `json:"foundationPercent"`
+}
+
+// GetRewardSplit returns the block reward split of the next block on top of the
+// current chain head. Blocks before the canxium fork pay no block reward.
+func (api *ChainAPI) GetRewardSplit() (*RewardSplit, error) {
+	var (
+		config = api.chain.Config()
+		header = &types.Header{Number: new(big.Int).Add(api.chain.CurrentHeader().Number, big1)}
+	)
+	if !config.IsCanxium(header.Number) {
+		return &RewardSplit{
+			Number:           (*hexutil.Big)(header.Number),
+			MinerReward:      new(hexutil.Big),
+			FoundationReward: new(hexutil.Big),
+		}, nil
+	}
+	reward, foundation := calculateRewards(config, header)
+
+	percent := CanxiumFoundationFirstYearRewardPercent.Uint64()
+	if split := config.RewardSplit(header.Number); split != nil {
+		percent = split.FoundationPercent
+	}
+	return &RewardSplit{
+		Number:            (*hexutil.Big)(header.Number),
+		MinerReward:       (*hexutil.Big)(reward),
+		FoundationReward:  (*hexutil.Big)(foundation),
+		FoundationPercent: hexutil.Uint64(percent),
+	}, nil
+}
+
+// ForkEra is the set of forks active at a given block and time, along with the
+// configured activation blocks and times of the forks affecting block rewards,
+// mining subsidies and base fees.
+type ForkEra struct {
+	Number                 hexutil.Uint64  `json:"number"`
+	Time                   hexutil.Uint64  `json:"time"`
+	Active                 []string        `json:"active"`
+	LondonBlock            *hexutil.Big    `json:"londonBlock"`
+	CanxiumBlock           *hexutil.Big    `json:"canxiumBlock"`
+	HydroBlock             *hexutil.Big    `json:"hydroBlock"`
+	MiningAlgorithmBlock   *hexutil.Big    `json:"miningAlgorithmBlock"`
+	MiningRewardGraceBlock *hexutil.Big    `json:"miningRewardGraceBlock"`
+	ShanghaiTime           *hexutil.Uint64 `json:"shanghaiTime"`
+	CancunTime             *hexutil.Uint64 `json:"cancunTime"`
+}
+
+// GetForkEra returns the forks active at the given block number and timestamp.
+// If no timestamp is given, the timestamp of the block itself is used, or the
+// timestamp of the current chain head for blocks past the head.
+func (api *ChainAPI) GetForkEra(number hexutil.Uint64, timestamp *hexutil.Uint64) (*ForkEra, error) {
+	config := api.chain.Config()
+	if timestamp == nil {
+		header := api.chain.GetHeaderByNumber(uint64(number))
+		if header == nil {
+			head := api.chain.CurrentHeader()
+			if uint64(number) <= head.Number.Uint64() {
+				return nil, fmt.Errorf("header #%d not found", number)
+			}
+			header = head
+		}
+		timestamp = (*hexutil.Uint64)(&header.Time)
+	}
+	var (
+		num    = new(big.Int).SetUint64(uint64(number))
+		active []string
+	)
+	for _, fork := range []struct {
+		name   string
+		active bool
+	}{
+		{"london", config.IsLondon(num)},
+		{"canxium", config.IsCanxium(num)},
+		{"hydro", config.IsHydro(num)},
+		{"miningAlgorithm", config.IsMiningAlgorithm(num)},
+		{"miningRewardGrace", config.IsMiningRewardGrace(num)},
+		{"shanghai", config.IsShanghai(uint64(*timestamp))},
+		{"cancun", config.IsCancun(uint64(*timestamp))},
+	} {
+		if fork.active {
+			active = append(active, fork.name)
+		}
+	}
+	return &ForkEra{
+		Number:                 number,
+		Time:                   *timestamp,
+		Active:                 active,
+		LondonBlock:            (*hexutil.Big)(config.LondonBlock),
+		CanxiumBlock:           (*hexutil.Big)(config.CanxiumBlock),
+		HydroBlock:             (*hexutil.Big)(config.HydroBlock),
+		MiningAlgorithmBlock:   (*hexutil.Big)(config.MiningAlgorithmBlock),
+		MiningRewardGraceBlock: (*hexutil.Big)(config.MiningRewardGraceBlock),
+		ShanghaiTime:           (*hexutil.Uint64)(config.ShanghaiTime),
+		CancunTime:             (*hexutil.Uint64)(config.CancunTime),
+	}, nil
+}
@@ -22,6 +22,7 @@ import (
 	"fmt"
 	"math/big"
 	"runtime"
+	"sync"
 	"time"
 
 	mapset "github.com/deckarep/golang-set/v2"
@@ -49,8 +50,8 @@ var (
 	CanxiumFoundationFirstYearRewardPercent = big.NewInt(25)    // First year Foudation reward: 25%
 	// offline mining
 	CanxiumMaxTransactionReward = big.NewInt(4250)
-	CanxiumMiningReduceBlock    = big.NewInt(432000) // Offline mining reward reduce 11.76% every 432000 blocks
-	CanxiumMiningReducePeriod   = big.NewInt(24)     // Max 24 months
+	CanxiumMiningReduceBlock    = params.CanxiumMiningReduceBlock // Offline mining reward reduce 11.76% every 432000 blocks
+	CanxiumMiningReducePeriod   = big.NewInt(24)                  // Max 24 months
 	CanxiumMiningPeriodPercent  = big.NewInt(8842)
 	// make sure miner set the correct input data for the transaction
 	CanxiumMiningTxDataLength = 36
@@ -76,7 +77,7 @@ var (
 	errInvalidPoW            = errors.New("invalid proof-of-work")
 	errDifficultyUnderValue  = errors.New("mining transaction difficulty under value")
 	errInvalidMiningTxType   = errors.New("invalid mining transaction type")
-	errInvalidMiningTxValue  = errors.New("invalid mining transaction value")
+	errNonPositiveMiningTx   = errors.New("non-positive mining transaction value")
 	ErrInvalidMiningReceiver = errors.New("invalid mining transaction receiver")
 	ErrInvalidMiningSender   = errors.New("invalid mining transaction sender")
 	ErrInvalidMiningInput    = errors.New("invalid mining transaction input data")
@@ -632,6 +633,11 @@ func (ethash *Ethash) VerifyTxSeal(config *params.ChainConfig, tx *types.Transac
 	if tx.Difficulty().Cmp(config.Ethash.MinimumDifficulty) < 0 {
 		return errDifficultyUnderValue
 	}
+	// Ensure the transaction carries a reward, before recovering the sender.
+	// Test networks may opt in to mining transactions waiving the reward.
+	if sign := tx.Value().Sign(); sign < 0 || (sign == 0 && !config.IsMiningRewardWaiver(block)) {
+		return errNonPositiveMiningTx
+	}
 	// Ensure signer and from are same to avoid pow relay attack
 	signer := types.MakeSigner(config, block)
 	from, err := types.Sender(signer, tx)
@@ -648,11 +654,26 @@ func (ethash *Ethash) VerifyTxSeal(config *params.ChainConfig, tx *types.Transac
 	}
 
 	// Ensure value is valid: reward * difficulty
-	subsidy := ethash.TransactionMiningSubsidy(config, block)
-	value := new(big.Int).Mul(subsidy, tx.Difficulty())
-	if tx.Value().Cmp(value) != 0 {
-		return errInvalidMiningTxValue
+	if err := misc.VerifyMiningTxValue(config, ethash.TransactionMiningSubsidy, block, tx); err != nil {
+		return err
+	}
+	// Before the mining algorithm fork every mining transaction was verified as
+	// ethash, whatever algorithm it declared. Keep doing so for those blocks.
+	if !config.IsMiningAlgorithm(block) {
+		return ethash.verifyEthashTxSeal(tx, fulldag)
+	}
+	// Verify the PoW with the algorithm the transaction was mined with
+	switch tx.Algorithm() {
+	case types.EthashAlgorithm:
+		return ethash.verifyEthashTxSeal(tx, fulldag)
+	default:
+		return errUnsupportedAlgorithm
 	}
+}
+
+// verifyEthashTxSeal checks whether an ethash mined offline mining transaction
+// satisfies its PoW difficulty requirements.
+func (ethash *Ethash) verifyEthashTxSeal(tx *types.Transaction, fulldag bool) error {
 	// Recompute the digest and PoW values, using tx nonce and the number of dataset
 	number := tx.Nonce()
 
@@ -725,17 +746,19 @@ func (ethash *Ethash) VerifyTxsSeal(config *params.ChainConfig, txs types.Transa
 		errors       = make([]error, len(txs))
 		numMiningTxs = int64(0)
 	)
+	for _, tx := range txs {
+		if tx.Type() == types.MiningTxType {
+			numMiningTxs++
+		}
+	}
 	for i := 0; i < workers; i++ {
 		go func() {
 			for index := range inputs {
-				if txs[index].Type() != types.MiningTxType {
-					errors[index] = nil
-					done <- index
-					continue
+				// Mining transactions are verified by the algorithm they were
+				// mined with, see VerifyTxSeal
+				if txs[index].Type() == types.MiningTxType {
+					errors[index] = ethash.VerifyTxSeal(config, txs[index], block, fulldag)
 				}
-
-				numMiningTxs += 1
-				errors[index] = ethash.VerifyTxSeal(config, txs[index], block, fulldag)
 				done <- index
 			}
 		}()
@@ -776,6 +799,72 @@ func (ethash *Ethash) VerifyTxsSeal(config *params.ChainConfig, txs types.Transa
 	return sealCh
 }
 
+// TxSealResult is the verification result of a single transaction in a batch
+// passed to VerifyTxsSealStream.
+type TxSealResult struct {
+	Index int   // Index of the transaction in the batch
+	Err   error // Verification error, nil if the seal is valid
+}
+
+// VerifyTxsSealStream is similar to VerifyTxsSeal, but instead of waiting for the
+// batch in order, it reports the result of every transaction as soon as its own
+// verification completes. Non mining transactions are reported as valid.
+//
+// The returned abort channel can be closed to stop verifying the remaining
+// transactions. The results channel is closed after all transactions have been
+// reported, or once the verifications in flight finished after an abort.
+func (ethash *Ethash) VerifyTxsSealStream(config *params.ChainConfig, txs types.Transactions, block *big.Int, fulldag bool) (chan<- struct{}, <-chan TxSealResult) {
+	var (
+		abort   = make(chan struct{})
+		results = make(chan TxSealResult, len(txs))
+	)
+	// If we're running a full engine faking, accept any input as valid
+	if ethash.config.PowMode == ModeFullFake || len(txs) == 0 {
+		for i := range txs {
+			results <- TxSealResult{Index: i}
+		}
+		close(results)
+		return abort, results
+	}
+	// Spawn as many workers as allowed threads
+	workers := runtime.GOMAXPROCS(0)
+	if len(txs) < workers {
+		workers = len(txs)
+	}
+	var (
+		inputs = make(chan int, len(txs))
+		pend   sync.WaitGroup
+	)
+	for i := range txs {
+		inputs <- i
+	}
+	close(inputs)
+
+	for i := 0; i < workers; i++ {
+		pend.Add(1)
+		go func() {
+			defer pend.Done()
+			for index := range inputs {
+				select {
+				case <-abort:
+					return
+				default:
+				}
+				var err error
+				if txs[index].Type() == types.MiningTxType {
+					err = ethash.VerifyTxSeal(config, txs[index], block, fulldag)
+				}
+				results <- TxSealResult{Index: index, Err: err}
+			}
+		}()
+	}
+	go func() {
+		pend.Wait()
+		close(results)
+	}()
+	return abort, results
+}
+
 // Prepare implements consensus.Engine, initializing the difficulty field of a
 // header to conform to the ethash protocol. The changes are done inline.
 func (ethash *Ethash) Prepare(chain consensus.ChainHeaderReader, header *types.Header) error {
@@ -850,29 +939,121 @@ func (ethash *Ethash) SealHash(header *types.Header) (hash common.Hash) {
 	return hash
 }
 
-// Calculate offline mining reward base on block number
-func (ethash *Ethash) TransactionMiningSubsidy(config *params.ChainConfig, block *big.Int) *big.Int {
+// Calculate offline mining reward base on block number and mining algorithm
+func (ethash *Ethash) TransactionMiningSubsidy(config *params.ChainConfig, block *big.Int, algorithm uint8) *big.Int {
 	if !config.IsHydro(block) {
 		return big0
 	}
+	maxReward, rewardPerHash := miningRewards(config, block, algorithm)
+
 	blockPassed := new(big.Int).Sub(block, config.HydroBlock)
 	period := new(big.Int).Div(blockPassed, CanxiumMiningReduceBlock)
 	if period.Cmp(big0) == 0 {
-		return CanxiumMaxTransactionReward
+		return maxReward
 	}
 
 	// reduce mining reward for max 24 period
 	if period.Cmp(CanxiumMiningReducePeriod) >= 0 {
-		return CanxiumRewardPerHash
+		return rewardPerHash
 	}
 
 	exp := new(big.Int).Exp(CanxiumMiningPeriodPercent, period, nil)
 	percentage := new(big.Int).Exp(big.NewInt(10000), period, nil)
-	periodReward := new(big.Int).Mul(CanxiumMaxTransactionReward, exp)
+	periodReward := new(big.Int).Mul(maxReward, exp)
 	subsidy := new(big.Int).Div(periodReward, percentage)
 	return subsidy
 }
 
+// OfflineMiningEmission returns the sum of the offline mining subsidies of the
+// given algorithm over the inclusive block range [from, to]. The subsidy only
+// changes once every CanxiumMiningReduceBlock blocks, so it's summed per period
+// instead of per block.
+func (ethash *Ethash) OfflineMiningEmission(config *params.ChainConfig, algorithm uint8, from, to *big.Int) *big.Int {
+	total := new(big.Int)
+	if config.HydroBlock == nil || from.Cmp(to) > 0 {
+		return total
+	}
+	// Blocks before the hydro fork have no subsidy, skip them
+	start := new(big.Int).Set(from)
+	if start.Cmp(config.HydroBlock) < 0 {
+		start.Set(config.HydroBlock)
+	}
+	for start.Cmp(to) <= 0 {
+		// Find the last block of the current period within the range, the
+		// subsidy doesn't change anymore once the reduction periods are over
+		end := new(big.Int).Set(to)
+
+		period := new(big.Int).Sub(start, config.HydroBlock)
+		period.Div(period, CanxiumMiningReduceBlock)
+		if period.Cmp(CanxiumMiningReducePeriod) < 0 {
+			last := new(big.Int).Add(period, big1)
+			last.Mul(last, CanxiumMiningReduceBlock)
+			last.Add(last, config.HydroBlock)
+			last.Sub(last, big1)
+			if last.Cmp(end) < 0 {
+				end = last
+			}
+		}
+		// The subsidy also changes when a configured reward schedule activates
+		if reward := config.MiningRewards[algorithm]; reward != nil && reward.Block != nil {
+			if reward.Block.Cmp(start) > 0 && reward.Block.Cmp(end) <= 0 {
+				end = new(big.Int).Sub(reward.Block, big1)
+			}
+		}
+		blocks := new(big.Int).Sub(end, start)
+		blocks.Add(blocks, big1)
+		total.Add(total, blocks.Mul(blocks, ethash.TransactionMiningSubsidy(config, start, algorithm)))
+
+		start = end.Add(end, big1)
+	}
+	return total
+}
+
+// MiningPeriod is the offline mining subsidy per difficulty hash during one of
+// the reduction periods following the hydro fork.
+type MiningPeriod struct {
+	Period     int      `json:"period"`
+	FirstBlock *big.Int `json:"firstBlock"`
+	Subsidy    *big.Int `json:"subsidy"`
+}
+
+// DumpOfflineMiningCurve returns the offline mining subsidy of the given
+// algorithm for every reduction period, with the last entry being the final
+// subsidy paid once the reduction periods are over.
+func (ethash *Ethash) DumpOfflineMiningCurve(config *params.ChainConfig, algorithm uint8) []MiningPeriod {
+	if config.HydroBlock == nil {
+		return nil
+	}
+	periods := int(CanxiumMiningReducePeriod.Int64())
+	curve := make([]MiningPeriod, 0, periods+1)
+	for period := 0; period <= periods; period++ {
+		first := new(big.Int).Mul(big.NewInt(int64(period)), CanxiumMiningReduceBlock)
+		first.Add(first, config.HydroBlock)
+		curve = append(curve, MiningPeriod{
+			Period:     period,
+			FirstBlock: first,
+			Subsidy:    ethash.TransactionMiningSubsidy(config, first, algorithm),
+		})
+	}
+	return curve
+}
+
+// miningRewards returns the first period and final reward per difficulty hash
+// of the given mining algorithm at the given block, falling back to the ethash
+// schedule if the chain config doesn't specify one active at that block.
+//
+// Before the mining algorithm fork every mining transaction is verified as
+// ethash, so it's also priced as ethash whatever algorithm it declares.
+func miningRewards(config *params.ChainConfig, block *big.Int, algorithm uint8) (*big.Int, *big.Int) {
+	if !config.IsMiningAlgorithm(block) {
+		algorithm = types.EthashAlgorithm
+	}
+	if reward := config.MiningReward(algorithm, block); reward != nil {
+		return reward.MaxReward, reward.RewardPerHash
+	}
+	return CanxiumMaxTransactionReward, CanxiumRewardPerHash
+}
+
 // AccumulateRewards credits the coinbase of the given block with the mining
 // reward. The total reward consists of the static block reward and rewards for
 // included uncles. The coinbase of each uncle block is also rewarded.
@@ -887,9 +1068,14 @@ func accumulateRewards(config *params.ChainConfig, state *state.StateDB, header
 	state.AddBalance(config.Foundation, foundation)
 }
 
+// calculateRewards splits the block reward of the given header between the miner
+// and the foundation wallet, based on the reward split active at that block.
 func calculateRewards(config *params.ChainConfig, header *types.Header) (*big.Int, *big.Int) {
 	blockReward := CanxiumBlockFirstYearReward
 	foundationPercent := CanxiumFoundationFirstYearRewardPercent
+	if split := config.RewardSplit(header.Number); split != nil {
+		foundationPercent = new(big.Int).SetUint64(split.FoundationPercent)
+	}
 	// Accumulate the rewards for the miner
 	reward := new(big.Int).Set(blockReward)
 	// send reward to foundation wallet
@@ -20,15 +20,26 @@ import (
 	crand "crypto/rand"
 	"encoding/binary"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"math/big"
 	"math/rand"
 	"os"
 	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/consensus/misc"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/params"
 )
 
@@ -186,3 +197,692 @@ func BenchmarkDifficultyCalculator(b *testing.B) {
 		}
 	})
 }
+
+func TestTransactionMiningSubsidyPerAlgorithm(t *testing.T) {
+	config := *params.TestChainConfig
+	config.HydroBlock = big.NewInt(100)
+	config.MiningAlgorithmBlock = big.NewInt(150)
+	config.MiningRewards = map[uint8]*params.MiningRewardConfig{
+		types.Sha256Algorithm: {Block: big.NewInt(200), MaxReward: big.NewInt(1000), RewardPerHash: big.NewInt(100)},
+	}
+	ethash := NewFaker()
+
+	tests := []struct {
+		block  int64
+		ethash int64
+		sha256 int64
+	}{
+		{99, 0, 0},                  // before the hydro fork
+		{100, 4250, 4250},           // first reduction period, before the sha256 schedule
+		{200, 4250, 1000},           // first reduction period
+		{100 + 432000, 3757, 884},   // second reduction period
+		{100 + 24*432000, 250, 100}, // reduction periods are over
+	}
+	for i, test := range tests {
+		block := big.NewInt(test.block)
+		if have := ethash.TransactionMiningSubsidy(&config, block, types.EthashAlgorithm); have.Cmp(big.NewInt(test.ethash)) != 0 {
+			t.Errorf("test %d: ethash subsidy mismatch: have %v, want %v", i, have, test.ethash)
+		}
+		if have := ethash.TransactionMiningSubsidy(&config, block, types.Sha256Algorithm); have.Cmp(big.NewInt(test.sha256)) != 0 {
+			t.Errorf("test %d: sha256 subsidy mismatch: have %v, want %v", i, have, test.sha256)
+		}
+	}
+}
+
+func TestOfflineMiningEmission(t *testing.T) {
+	config := *params.TestChainConfig
+	config.HydroBlock = big.NewInt(100)
+	config.MiningAlgorithmBlock = big.NewInt(105)
+	config.MiningRewards = map[uint8]*params.MiningRewardConfig{
+		types.Sha256Algorithm: {Block: big.NewInt(105), MaxReward: big.NewInt(1000), RewardPerHash: big.NewInt(100)},
+	}
+	ethash := NewFaker()
+
+	reduce := CanxiumMiningReduceBlock.Int64()
+	last := 100 + CanxiumMiningReducePeriod.Int64()*reduce
+	tests := []struct {
+		from, to int64
+	}{
+		{0, 50},                              // entirely before the hydro fork
+		{90, 110},                            // across the hydro fork
+		{100, 100},                           // single block
+		{110, 100},                           // empty range
+		{100 + reduce - 5, 100 + reduce + 5}, // across a reduction period
+		{50, 100 + 2*reduce + 10},            // across multiple reduction periods
+		{last - 10, last + 10},               // across the end of the reduction periods
+	}
+	for i, test := range tests {
+		for _, algorithm := range []uint8{types.EthashAlgorithm, types.Sha256Algorithm} {
+			want := new(big.Int)
+			for n := test.from; n <= test.to; n++ {
+				want.Add(want, ethash.TransactionMiningSubsidy(&config, big.NewInt(n), algorithm))
+			}
+			have := ethash.OfflineMiningEmission(&config, algorithm, big.NewInt(test.from), big.NewInt(test.to))
+			if have.Cmp(want) != 0 {
+				t.Errorf("test %d, algorithm %d: emission mismatch: have %v, want %v", i, algorithm, have, want)
+			}
+		}
+	}
+}
+
+func TestDumpOfflineMiningCurve(t *testing.T) {
+	config := *params.TestChainConfig
+	config.HydroBlock = big.NewInt(100)
+	ethash := NewFaker()
+
+	curve := ethash.DumpOfflineMiningCurve(&config, types.EthashAlgorithm)
+	if len(curve) != int(CanxiumMiningReducePeriod.Int64())+1 {
+		t.Fatalf("curve length mismatch: have %d, want %d", len(curve), CanxiumMiningReducePeriod.Int64()+1)
+	}
+	if curve[0].Subsidy.Cmp(CanxiumMaxTransactionReward) != 0 {
+		t.Errorf("first period subsidy mismatch: have %v, want %v", curve[0].Subsidy, CanxiumMaxTransactionReward)
+	}
+	if last := curve[len(curve)-1]; last.Subsidy.Cmp(CanxiumRewardPerHash) != 0 {
+		t.Errorf("final subsidy mismatch: have %v, want %v", last.Subsidy, CanxiumRewardPerHash)
+	}
+	for i, period := range curve {
+		if period.Period != i {
+			t.Errorf("period %d: index mismatch: have %d", i, period.Period)
+		}
+		// Every block of the period must pay the reported subsidy
+		last := new(big.Int).Add(period.FirstBlock, new(big.Int).Sub(CanxiumMiningReduceBlock, big1))
+		if subsidy := ethash.TransactionMiningSubsidy(&config, last, types.EthashAlgorithm); subsidy.Cmp(period.Subsidy) != 0 {
+			t.Errorf("period %d: last block subsidy mismatch: have %v, want %v", i, subsidy, period.Subsidy)
+		}
+		if i > 0 && period.Subsidy.Cmp(curve[i-1].Subsidy) > 0 {
+			t.Errorf("period %d: subsidy increased: have %v, previous %v", i, period.Subsidy, curve[i-1].Subsidy)
+		}
+	}
+	config.HydroBlock = nil
+	if curve := ethash.DumpOfflineMiningCurve(&config, types.EthashAlgorithm); curve != nil {
+		t.Errorf("curve without hydro fork: have %v, want nil", curve)
+	}
+}
+
+func TestVerifyTxsSealStream(t *testing.T) {
+	config := *params.TestChainConfig
+	config.HydroBlock = big.NewInt(10)
+
+	var txs types.Transactions
+	for i := 0; i < 32; i++ {
+		if i%4 == 0 {
+			txs = append(txs, types.NewTransaction(uint64(i), common.Address{}, new(big.Int), 21000, new(big.Int), nil))
+			continue
+		}
+		txs = append(txs, types.NewTx(&types.MiningTx{Nonce: uint64(i), Difficulty: big.NewInt(1), To: config.MiningContract}))
+	}
+	for _, block := range []int64{9, 10} {
+		var (
+			ethash = NewFakeDelayer(time.Millisecond)
+			seen   = make(map[int]bool)
+		)
+		_, results := ethash.VerifyTxsSealStream(&config, txs, big.NewInt(block), false)
+		for res := range results {
+			if seen[res.Index] {
+				t.Fatalf("block %d: transaction %d reported twice", block, res.Index)
+			}
+			seen[res.Index] = true
+
+			// Mining transactions are only valid after the hydro fork
+			var want error
+			if txs[res.Index].IsMiningTx() && block < 10 {
+				want = types.ErrTxTypeNotSupported
+			}
+			if res.Err != want {
+				t.Errorf("block %d: transaction %d error mismatch: have %v, want %v", block, res.Index, res.Err, want)
+			}
+		}
+		if len(seen) != len(txs) {
+			t.Errorf("block %d: reported transactions mismatch: have %d, want %d", block, len(seen), len(txs))
+		}
+	}
+}
+
+// Tests that aborting a streamed batch verification stops the workers from
+// verifying the remaining transactions.
+func TestVerifyTxsSealStreamAbort(t *testing.T) {
+	config := *params.TestChainConfig
+	config.HydroBlock = big.NewInt(0)
+
+	var txs types.Transactions
+	for i := 0; i < 1000; i++ {
+		txs = append(txs, types.NewTx(&types.MiningTx{Nonce: uint64(i), Difficulty: big.NewInt(1), To: config.MiningContract}))
+	}
+	ethash := NewFakeDelayer(10 * time.Millisecond)
+
+	abort, results := ethash.VerifyTxsSealStream(&config, txs, big.NewInt(1), false)
+	<-results
+	close(abort)
+
+	// Workers finish the verifications they were running when the batch was
+	// aborted, but don't start any new ones
+	reported := 1
+	for range results {
+		reported++
+	}
+	if limit := 4 * runtime.GOMAXPROCS(0); reported > limit {
+		t.Errorf("reported transactions mismatch: have %d, want at most %d", reported, limit)
+	}
+}
+
+// testChainReader is a header chain reader serving a single head header.
+type testChainReader struct {
+	config  *params.ChainConfig
+	head    *types.Header
+	headers map[uint64]*types.Header
+}
+
+func (r *testChainReader) Config() *params.ChainConfig                    { return r.config }
+func (r *testChainReader) CurrentHeader() *types.Header                   { return r.head }
+func (r *testChainReader) GetHeader(common.Hash, uint64) *types.Header    { return nil }
+func (r *testChainReader) GetHeaderByNumber(n uint64) *types.Header       { return r.headers[n] }
+func (r *testChainReader) GetHeaderByHash(common.Hash) *types.Header      { return nil }
+func (r *testChainReader) GetTd(hash common.Hash, number uint64) *big.Int { return nil }
+
+func TestGetForkEra(t *testing.T) {
+	config := *params.TestChainConfig
+	config.LondonBlock = big.NewInt(0)
+	config.CanxiumBlock = big.NewInt(10)
+	config.HydroBlock = big.NewInt(20)
+	config.MiningAlgorithmBlock = big.NewInt(21)
+	config.ShanghaiTime = newUint64(1000)
+	config.CancunTime = nil
+
+	// The chain head is past shanghai, but the blocks before it are not
+	headers := make(map[uint64]*types.Header)
+	for n := uint64(0); n <= 15; n++ {
+		headers[n] = &types.Header{Number: new(big.Int).SetUint64(n), Time: n * 100}
+	}
+	api := &ChainAPI{chain: &testChainReader{config: &config, head: headers[15], headers: headers}}
+	tests := []struct {
+		number uint64
+		time   *uint64
+		want   uint64
+		active []string
+	}{
+		{0, nil, 0, []string{"london"}},
+		{9, newUint64(999), 999, []string{"london"}},
+		{10, nil, 1000, []string{"london", "canxium", "shanghai"}},
+		{9, nil, 900, []string{"london"}},
+		{19, newUint64(1000), 1000, []string{"london", "canxium", "shanghai"}},
+		{20, newUint64(1000), 1000, []string{"london", "canxium", "hydro", "shanghai"}},
+		{20, nil, 1500, []string{"london", "canxium", "hydro", "shanghai"}}, // past the head
+		{21, nil, 1500, []string{"london", "canxium", "hydro", "miningAlgorithm", "shanghai"}},
+	}
+	for i, test := range tests {
+		era, err := api.GetForkEra(hexutil.Uint64(test.number), (*hexutil.Uint64)(test.time))
+		if err != nil {
+			t.Fatalf("test %d: failed to get fork era: %v", i, err)
+		}
+		if !reflect.DeepEqual(era.Active, test.active) {
+			t.Errorf("test %d: active forks mismatch: have %v, want %v", i, era.Active, test.active)
+		}
+		if uint64(era.Time) != test.want {
+			t.Errorf("test %d: time mismatch: have %d, want %d", i, era.Time, test.want)
+		}
+		if era.HydroBlock.ToInt().Cmp(config.HydroBlock) != 0 || era.MiningAlgorithmBlock.ToInt().Cmp(config.MiningAlgorithmBlock) != 0 || uint64(*era.ShanghaiTime) != *config.ShanghaiTime {
+			t.Errorf("test %d: fork activations mismatch: have hydro %v mining algorithm %v shanghai %v", i, era.HydroBlock, era.MiningAlgorithmBlock, era.ShanghaiTime)
+		}
+	}
+}
+
+func newUint64(val uint64) *uint64 { return &val }
+
+func TestRewardSplit(t *testing.T) {
+	config := *params.TestChainConfig
+	config.CanxiumBlock = big.NewInt(50)
+	config.Foundation = common.HexToAddress("0xf0")
+	config.RewardSplits = []params.RewardSplitConfig{
+		{Block: big.NewInt(100), FoundationPercent: 10},
+		{Block: big.NewInt(200), FoundationPercent: 2},
+	}
+	if err := config.CheckConfigForkOrder(); err != nil {
+		t.Fatalf("invalid reward splits: %v", err)
+	}
+	tests := []struct {
+		number  int64
+		percent int64
+	}{
+		{49, 0},  // before the canxium fork, no block reward
+		{50, 25}, // default split
+		{99, 25},
+		{100, 10}, // first configured split
+		{199, 10},
+		{200, 2}, // second configured split
+		{1000, 2},
+	}
+	for i, test := range tests {
+		var (
+			coinbase = common.HexToAddress("0xc0")
+			header   = &types.Header{Number: big.NewInt(test.number), Coinbase: coinbase}
+			statedb  = newTestState(t)
+
+			foundation = new(big.Int).Div(new(big.Int).Mul(CanxiumBlockFirstYearReward, big.NewInt(test.percent)), big100)
+			miner      = new(big.Int).Sub(CanxiumBlockFirstYearReward, foundation)
+		)
+		if !config.IsCanxium(header.Number) {
+			foundation, miner = new(big.Int), new(big.Int)
+		}
+		NewFaker().Finalize(&testChainReader{config: &config}, header, statedb, nil, nil, nil)
+		if have := statedb.GetBalance(coinbase); have.Cmp(miner) != 0 {
+			t.Errorf("test %d: miner reward mismatch: have %v, want %v", i, have, miner)
+		}
+		if have := statedb.GetBalance(config.Foundation); have.Cmp(foundation) != 0 {
+			t.Errorf("test %d: foundation reward mismatch: have %v, want %v", i, have, foundation)
+		}
+		// The RPC reports the split of the block on top of the current head
+		api := &ChainAPI{chain: &testChainReader{config: &config, head: &types.Header{Number: big.NewInt(test.number - 1)}}}
+		split, err := api.GetRewardSplit()
+		if err != nil {
+			t.Fatalf("test %d: failed to retrieve reward split: %v", i, err)
+		}
+		if uint64(split.FoundationPercent) != uint64(test.percent) {
+			t.Errorf("test %d: foundation percent mismatch: have %d, want %d", i, split.FoundationPercent, test.percent)
+		}
+		if split.MinerReward.ToInt().Cmp(miner) != 0 || split.FoundationReward.ToInt().Cmp(foundation) != 0 {
+			t.Errorf("test %d: rpc reward split mismatch: have %v/%v, want %v/%v", i, split.MinerReward, split.FoundationReward, miner, foundation)
+		}
+	}
+}
+
+func TestRewardSplitConfig(t *testing.T) {
+	for i, splits := range [][]params.RewardSplitConfig{
+		{{Block: nil, FoundationPercent: 10}},
+		{{Block: big.NewInt(1), FoundationPercent: 101}},
+		{{Block: big.NewInt(2), FoundationPercent: 10}, {Block: big.NewInt(1), FoundationPercent: 10}},
+	} {
+		config := *params.TestChainConfig
+		config.RewardSplits = splits
+		if err := config.CheckConfigForkOrder(); err == nil {
+			t.Errorf("test %d: expected invalid reward splits", i)
+		}
+	}
+}
+
+func TestMiningRewardConfig(t *testing.T) {
+	for i, reward := range []*params.MiningRewardConfig{
+		{Block: nil, MaxReward: big.NewInt(1000), RewardPerHash: big.NewInt(100)},
+		{Block: big.NewInt(1), MaxReward: nil, RewardPerHash: big.NewInt(100)},
+		{Block: big.NewInt(1), MaxReward: big.NewInt(1000), RewardPerHash: nil},
+		{Block: big.NewInt(1), MaxReward: big.NewInt(0), RewardPerHash: big.NewInt(100)},
+		{Block: big.NewInt(1), MaxReward: big.NewInt(1000), RewardPerHash: big.NewInt(0)},
+	} {
+		config := *params.TestChainConfig
+		config.MiningAlgorithmBlock = big.NewInt(0)
+		config.MiningRewards = map[uint8]*params.MiningRewardConfig{types.Sha256Algorithm: reward}
+		if err := config.CheckConfigForkOrder(); err == nil {
+			t.Errorf("test %d: expected invalid mining reward", i)
+		}
+	}
+	// Non-ethash schedules can't activate before the mining algorithm fork
+	reward := &params.MiningRewardConfig{Block: big.NewInt(10), MaxReward: big.NewInt(1000), RewardPerHash: big.NewInt(100)}
+	for i, fork := range []*big.Int{nil, big.NewInt(11)} {
+		config := *params.TestChainConfig
+		config.MiningAlgorithmBlock = fork
+		config.MiningRewards = map[uint8]*params.MiningRewardConfig{types.Sha256Algorithm: reward}
+		if err := config.CheckConfigForkOrder(); err == nil {
+			t.Errorf("fork test %d: expected invalid mining reward ordering", i)
+		}
+		config.MiningRewards = map[uint8]*params.MiningRewardConfig{types.EthashAlgorithm: reward}
+		if err := config.CheckConfigForkOrder(); err != nil {
+			t.Errorf("fork test %d: unexpected ethash mining reward error: %v", i, err)
+		}
+	}
+	config := *params.TestChainConfig
+	config.MiningAlgorithmBlock = big.NewInt(10)
+	config.MiningRewards = map[uint8]*params.MiningRewardConfig{types.Sha256Algorithm: reward}
+	if err := config.CheckConfigForkOrder(); err != nil {
+		t.Errorf("unexpected mining reward error at the fork: %v", err)
+	}
+}
+
+func newTestState(t *testing.T) *state.StateDB {
+	statedb, err := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	if err != nil {
+		t.Fatalf("failed to create state: %v", err)
+	}
+	return statedb
+}
+
+var testMiningKey, _ = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+
+// newTestMiningConfig returns a chain config with offline mining enabled from
+// genesis and a minimum mining difficulty low enough to seal in tests.
+func newTestMiningConfig() *params.ChainConfig {
+	config := *params.TestChainConfig
+	config.HydroBlock = big.NewInt(0)
+	config.MiningContract = common.HexToAddress("0x6c6f6c6c6f6c6c6f6c6c6f6c6c6f6c6c6f6c6c6f")
+	config.MiningAlgorithmBlock = big.NewInt(0)
+	config.Ethash = &params.EthashConfig{MinimumDifficulty: big.NewInt(1)}
+	return &config
+}
+
+// newTestMiningTx creates an offline mining transaction paying the subsidy of
+// the given block, seals it with the ethash test cache and signs it.
+func newTestMiningTx(t *testing.T, ethash *Ethash, config *params.ChainConfig, block *big.Int, difficulty int64) *types.Transaction {
+	from := crypto.PubkeyToAddress(testMiningKey.PublicKey)
+	inner := &types.MiningTx{
+		ChainID:    config.ChainID,
+		Nonce:      0,
+		GasTipCap:  new(big.Int),
+		GasFeeCap:  new(big.Int),
+		Gas:        100000,
+		From:       from,
+		To:         config.MiningContract,
+		Value:      new(big.Int).Mul(ethash.TransactionMiningSubsidy(config, block, types.EthashAlgorithm), big.NewInt(difficulty)),
+		Data:       append(common.CopyBytes(CanxiumMiningTxDataMethod), common.LeftPadBytes(from.Bytes(), 20)...),
+		Algorithm:  types.EthashAlgorithm,
+		Difficulty: big.NewInt(difficulty),
+	}
+	return sealTestMiningTx(t, ethash, config, block, inner)
+}
+
+// sealTestMiningTx searches a PoW nonce for the given mining transaction with
+// the ethash test cache and signs it.
+func sealTestMiningTx(t *testing.T, ethash *Ethash, config *params.ChainConfig, block *big.Int, inner *types.MiningTx) *types.Transaction {
+	var (
+		hash   = types.NewTx(inner).SealHash().Bytes()
+		target = new(big.Int).Div(two256, inner.Difficulty)
+		cache  = ethash.cache(inner.Nonce)
+	)
+	for nonce := uint64(0); ; nonce++ {
+		digest, result := hashimotoLight(32*1024, cache.cache, hash, nonce)
+		if new(big.Int).SetBytes(result).Cmp(target) <= 0 {
+			inner.PowNonce = types.EncodePowNonce(nonce)
+			inner.MixDigest = common.BytesToHash(digest)
+			break
+		}
+	}
+	tx, err := types.SignNewTx(testMiningKey, types.MakeSigner(config, block), inner)
+	if err != nil {
+		t.Fatalf("failed to sign mining transaction: %v", err)
+	}
+	return tx
+}
+
+// resignTestMiningTx applies the given modification to a mining transaction and
+// signs it again.
+func resignTestMiningTx(t *testing.T, config *params.ChainConfig, block *big.Int, tx *types.Transaction, modify func(inner *types.MiningTx)) *types.Transaction {
+	v, r, s := tx.RawSignatureValues()
+	inner := &types.MiningTx{
+		ChainID:    tx.ChainId(),
+		Nonce:      tx.Nonce(),
+		GasTipCap:  tx.GasTipCap(),
+		GasFeeCap:  tx.GasFeeCap(),
+		Gas:        tx.Gas(),
+		From:       tx.From(),
+		To:         *tx.To(),
+		Value:      tx.Value(),
+		Data:       tx.Data(),
+		Algorithm:  tx.Algorithm(),
+		Difficulty: tx.Difficulty(),
+		PowNonce:   types.EncodePowNonce(tx.PowNonce()),
+		MixDigest:  tx.MixDigest(),
+		V:          v,
+		R:          r,
+		S:          s,
+	}
+	modify(inner)
+	tx, err := types.SignNewTx(testMiningKey, types.MakeSigner(config, block), inner)
+	if err != nil {
+		t.Fatalf("failed to sign mining transaction: %v", err)
+	}
+	return tx
+}
+
+func TestVerifyTxSealValue(t *testing.T) {
+	var (
+		ethash = NewTester(nil, false)
+		config = newTestMiningConfig()
+		block  = big.NewInt(1)
+		valid  = newTestMiningTx(t, ethash, config, block, 16)
+	)
+	defer ethash.Close()
+
+	if err := ethash.VerifyTxSeal(config, valid, block, false); err != nil {
+		t.Fatalf("failed to verify valid mining transaction: %v", err)
+	}
+	invalid := resignTestMiningTx(t, config, block, valid, func(inner *types.MiningTx) {
+		inner.Value = new(big.Int).Sub(inner.Value, big1)
+	})
+	err := ethash.VerifyTxSeal(config, invalid, block, false)
+	if !errors.Is(err, misc.ErrInvalidMiningTxValue) {
+		t.Fatalf("error mismatch: have %v, want %v", err, misc.ErrInvalidMiningTxValue)
+	}
+	if want := fmt.Sprintf("have %v, want %v", invalid.Value(), valid.Value()); !strings.Contains(err.Error(), want) {
+		t.Errorf("error %q doesn't report the expected values %q", err, want)
+	}
+}
+
+// Tests that a mining transaction carrying a valid PoW nonce but a bogus mix
+// digest is rejected.
+func TestVerifyTxSealMixDigest(t *testing.T) {
+	var (
+		ethash = NewTester(nil, false)
+		config = newTestMiningConfig()
+		block  = big.NewInt(1)
+		valid  = newTestMiningTx(t, ethash, config, block, 16)
+	)
+	defer ethash.Close()
+
+	if err := ethash.VerifyTxSeal(config, valid, block, false); err != nil {
+		t.Fatalf("failed to verify valid mining transaction: %v", err)
+	}
+	tampered := resignTestMiningTx(t, config, block, valid, func(inner *types.MiningTx) {
+		inner.MixDigest[0] ^= 0xff
+	})
+	if tampered.SealHash() != valid.SealHash() {
+		t.Fatalf("mix digest is part of the seal hash")
+	}
+	if err := ethash.VerifyTxSeal(config, tampered, block, false); err != errInvalidMixDigest {
+		t.Errorf("error mismatch: have %v, want %v", err, errInvalidMixDigest)
+	}
+}
+
+// Tests that mining transactions without a positive value are rejected early
+// with a dedicated error.
+func TestVerifyTxSealNonPositiveValue(t *testing.T) {
+	var (
+		ethash = NewTester(nil, false)
+		config = newTestMiningConfig()
+		block  = big.NewInt(1)
+	)
+	defer ethash.Close()
+
+	for _, value := range []*big.Int{big.NewInt(0), big.NewInt(-1)} {
+		// Negative values can't be RLP encoded, so the transaction isn't signed
+		tx := types.NewTx(&types.MiningTx{
+			ChainID:    config.ChainID,
+			To:         config.MiningContract,
+			Value:      value,
+			Algorithm:  types.EthashAlgorithm,
+			Difficulty: big.NewInt(16),
+		})
+		if err := ethash.VerifyTxSeal(config, tx, block, false); err != errNonPositiveMiningTx {
+			t.Errorf("value %v: error mismatch: have %v, want %v", value, err, errNonPositiveMiningTx)
+		}
+	}
+}
+
+// Tests that zero value mining transactions are only accepted on networks
+// opting in to the mining reward waiver.
+func TestVerifyTxSealRewardWaiver(t *testing.T) {
+	var (
+		ethash = NewTester(nil, false)
+		block  = big.NewInt(1)
+	)
+	defer ethash.Close()
+
+	mainnet := newTestMiningConfig()
+	testnet := newTestMiningConfig()
+	testnet.MiningRewardWaiverBlock = big.NewInt(1)
+	pending := newTestMiningConfig()
+	pending.MiningRewardWaiverBlock = big.NewInt(2)
+
+	from := crypto.PubkeyToAddress(testMiningKey.PublicKey)
+	tx := sealTestMiningTx(t, ethash, testnet, block, &types.MiningTx{
+		ChainID:    testnet.ChainID,
+		GasTipCap:  new(big.Int),
+		GasFeeCap:  new(big.Int),
+		Gas:        100000,
+		From:       from,
+		To:         testnet.MiningContract,
+		Value:      new(big.Int),
+		Data:       append(common.CopyBytes(CanxiumMiningTxDataMethod), common.LeftPadBytes(from.Bytes(), 20)...),
+		Algorithm:  types.EthashAlgorithm,
+		Difficulty: big.NewInt(16),
+	})
+	if err := ethash.VerifyTxSeal(mainnet, tx, block, false); err != errNonPositiveMiningTx {
+		t.Errorf("without waiver: error mismatch: have %v, want %v", err, errNonPositiveMiningTx)
+	}
+	if err := ethash.VerifyTxSeal(pending, tx, block, false); err != errNonPositiveMiningTx {
+		t.Errorf("before waiver fork: error mismatch: have %v, want %v", err, errNonPositiveMiningTx)
+	}
+	if err := ethash.VerifyTxSeal(testnet, tx, block, false); err != nil {
+		t.Errorf("with waiver: unexpected error: %v", err)
+	}
+	// The waiver doesn't allow arbitrary values, only the full subsidy or nothing
+	partial := resignTestMiningTx(t, testnet, block, newTestMiningTx(t, ethash, testnet, block, 16), func(inner *types.MiningTx) {
+		inner.Value = big.NewInt(1)
+	})
+	if err := ethash.VerifyTxSeal(testnet, partial, block, false); !errors.Is(err, misc.ErrInvalidMiningTxValue) {
+		t.Errorf("partial value: error mismatch: have %v, want %v", err, misc.ErrInvalidMiningTxValue)
+	}
+}
+
+// Tests that verifying mining transactions against the light cache and against
+// the full dataset yield the same results.
+func TestVerifyTxSealLightFull(t *testing.T) {
+	var (
+		ethash = NewTester(nil, false)
+		config = newTestMiningConfig()
+		block  = big.NewInt(1)
+		valid  = newTestMiningTx(t, ethash, config, block, 16)
+	)
+	defer ethash.Close()
+
+	// Generate the dataset up front, full verification falls back to the
+	// light cache while it's being generated
+	if dataset := ethash.dataset(valid.Nonce(), false); !dataset.generated() {
+		t.Fatalf("failed to generate dataset")
+	}
+	txs := []*types.Transaction{
+		valid,
+		resignTestMiningTx(t, config, block, valid, func(inner *types.MiningTx) {
+			inner.MixDigest[0] ^= 0xff
+		}),
+		resignTestMiningTx(t, config, block, valid, func(inner *types.MiningTx) {
+			inner.PowNonce = types.EncodePowNonce(valid.PowNonce() + 1)
+		}),
+	}
+	for i, tx := range txs {
+		light := ethash.VerifyTxSeal(config, tx, block, false)
+		full := ethash.VerifyTxSeal(config, tx, block, true)
+		if light != full {
+			t.Errorf("tx %d: light and full verification disagree: light %v, full %v", i, light, full)
+		}
+		if (i == 0) != (light == nil) {
+			t.Errorf("tx %d: unexpected verification result: %v", i, light)
+		}
+	}
+}
+
+// Tests that mining transactions are only verified with their declared mining
+// algorithm from the mining algorithm fork on, and as ethash before it.
+func TestVerifyTxSealAlgorithmFork(t *testing.T) {
+	var (
+		ethash = NewTester(nil, false)
+		config = newTestMiningConfig()
+	)
+	defer ethash.Close()
+	config.MiningAlgorithmBlock = big.NewInt(2)
+
+	for _, algorithm := range []uint8{types.NoneAlgorithm, types.Sha256Algorithm, 0xff} {
+		from := crypto.PubkeyToAddress(testMiningKey.PublicKey)
+		tx := sealTestMiningTx(t, ethash, config, big.NewInt(1), &types.MiningTx{
+			ChainID:    config.ChainID,
+			GasTipCap:  new(big.Int),
+			GasFeeCap:  new(big.Int),
+			Gas:        100000,
+			From:       from,
+			To:         config.MiningContract,
+			Value:      new(big.Int).Mul(ethash.TransactionMiningSubsidy(config, big.NewInt(1), types.EthashAlgorithm), big.NewInt(16)),
+			Data:       append(common.CopyBytes(CanxiumMiningTxDataMethod), common.LeftPadBytes(from.Bytes(), 20)...),
+			Algorithm:  algorithm,
+			Difficulty: big.NewInt(16),
+		})
+		if err := ethash.VerifyTxSeal(config, tx, big.NewInt(1), false); err != nil {
+			t.Errorf("algorithm %d: before fork: unexpected error: %v", algorithm, err)
+		}
+		if err := ethash.VerifyTxSeal(config, tx, big.NewInt(2), false); err != errUnsupportedAlgorithm {
+			t.Errorf("algorithm %d: at fork: error mismatch: have %v, want %v", algorithm, err, errUnsupportedAlgorithm)
+		}
+	}
+}
+
+// Tests that a mining transaction declaring another algorithm but sealed with
+// ethash before the mining algorithm fork can't claim that algorithm's reward,
+// even if the chain config schedules it early.
+func TestVerifyTxSealAlgorithmForkReward(t *testing.T) {
+	var (
+		ethash = NewTester(nil, false)
+		config = newTestMiningConfig()
+		block  = big.NewInt(1)
+	)
+	defer ethash.Close()
+	config.MiningAlgorithmBlock = big.NewInt(2)
+	config.MiningRewards = map[uint8]*params.MiningRewardConfig{
+		types.Sha256Algorithm: {
+			Block:         big.NewInt(0),
+			MaxReward:     new(big.Int).Mul(ethash.TransactionMiningSubsidy(config, block, types.EthashAlgorithm), big.NewInt(2)),
+			RewardPerHash: big.NewInt(1),
+		},
+	}
+	from := crypto.PubkeyToAddress(testMiningKey.PublicKey)
+	tx := sealTestMiningTx(t, ethash, config, block, &types.MiningTx{
+		ChainID:    config.ChainID,
+		GasTipCap:  new(big.Int),
+		GasFeeCap:  new(big.Int),
+		Gas:        100000,
+		From:       from,
+		To:         config.MiningContract,
+		Value:      new(big.Int).Mul(config.MiningRewards[types.Sha256Algorithm].MaxReward, big.NewInt(16)),
+		Data:       append(common.CopyBytes(CanxiumMiningTxDataMethod), common.LeftPadBytes(from.Bytes(), 20)...),
+		Algorithm:  types.Sha256Algorithm,
+		Difficulty: big.NewInt(16),
+	})
+	if err := ethash.VerifyTxSeal(config, tx, block, false); !errors.Is(err, misc.ErrInvalidMiningTxValue) {
+		t.Errorf("error mismatch: have %v, want %v", err, misc.ErrInvalidMiningTxValue)
+	}
+}
+
+// Tests that a batch mixing mining algorithms verifies every transaction with
+// its own algorithm, rejecting the algorithms ethash can't verify.
+func TestVerifyTxsSealMixedAlgorithms(t *testing.T) {
+	var (
+		ethash = NewTester(nil, false)
+		config = newTestMiningConfig()
+		block  = big.NewInt(1)
+		valid  = newTestMiningTx(t, ethash, config, block, 16)
+		sha256 = resignTestMiningTx(t, config, block, valid, func(inner *types.MiningTx) {
+			inner.Algorithm = types.Sha256Algorithm
+		})
+		transfer = types.NewTransaction(0, common.Address{}, new(big.Int), params.TxGas, new(big.Int), nil)
+	)
+	defer ethash.Close()
+
+	if count := <-ethash.VerifyTxsSeal(config, types.Transactions{transfer, valid}, block, false); count != 1 {
+		t.Errorf("ethash batch: count mismatch: have %d, want 1", count)
+	}
+	batch := types.Transactions{transfer, valid, sha256}
+	if count := <-ethash.VerifyTxsSeal(config, batch, block, false); count != -1 {
+		t.Errorf("mixed batch: count mismatch: have %d, want -1", count)
+	}
+	want := []error{nil, nil, errUnsupportedAlgorithm}
+	_, results := ethash.VerifyTxsSealStream(config, batch, block, false)
+	for result := range results {
+		if result.Err != want[result.Index] {
+			t.Errorf("tx %d: error mismatch: have %v, want %v", result.Index, result.Err, want[result.Index])
+		}
+	}
+}
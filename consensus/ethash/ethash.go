@@ -680,7 +680,9 @@ func (ethash *Ethash) Hashrate() float64 {
 // APIs implements consensus.Engine, returning the user facing RPC APIs.
 func (ethash *Ethash) APIs(chain consensus.ChainHeaderReader) []rpc.API {
 	// In order to ensure backward compatibility, we exposes ethash RPC APIs
-	// to both eth and ethash namespaces.
+	// to both eth and ethash namespaces. Chain information is only exposed in
+	// the ethash namespace and methods consuming local resources only in the
+	// miner namespace.
 	return []rpc.API{
 		{
 			Namespace: "eth",
@@ -690,6 +692,14 @@ func (ethash *Ethash) APIs(chain consensus.ChainHeaderReader) []rpc.API {
 			Namespace: "ethash",
 			Service:   &API{ethash},
 		},
+		{
+			Namespace: "ethash",
+			Service:   &ChainAPI{chain: chain},
+		},
+		{
+			Namespace: "miner",
+			Service:   &MinerAPI{ethash: ethash},
+		},
 	}
 }
 
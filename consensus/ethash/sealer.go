@@ -28,6 +28,7 @@ import (
 	"net/http"
 	"runtime"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -42,8 +43,10 @@ const (
 )
 
 var (
-	errNoMiningWork      = errors.New("no mining work available yet")
-	errInvalidSealResult = errors.New("invalid or stale proof-of-work solution")
+	errNoMiningWork         = errors.New("no mining work available yet")
+	errInvalidSealResult    = errors.New("invalid or stale proof-of-work solution")
+	errUnsupportedAlgorithm = errors.New("unsupported mining algorithm")
+	errFakeBenchmark        = errors.New("benchmark not available with a fake proof-of-work")
 )
 
 // Seal implements consensus.Engine, attempting to find a nonce that satisfies
@@ -187,6 +190,69 @@ search:
 	runtime.KeepAlive(dataset)
 }
 
+// benchmark runs the hashing routine of the given mining algorithm on all the
+// mining threads for the given duration, without sealing anything, and returns
+// the number of hashes computed per second.
+//
+// The benchmark hashes against the verification cache instead of the full
+// mining dataset, so it never generates a DAG on demand. The measured rate is
+// lower than the rate of a miner with the dataset in memory, but comparable
+// across nodes and algorithms.
+func (ethash *Ethash) benchmark(algorithm uint8, duration time.Duration) (uint64, error) {
+	if algorithm != types.EthashAlgorithm {
+		return 0, errUnsupportedAlgorithm
+	}
+	// If we're running a fake PoW, there's no hashing to benchmark
+	if ethash.config.PowMode == ModeFake || ethash.config.PowMode == ModeFullFake {
+		return 0, errFakeBenchmark
+	}
+	// If we're running a shared PoW, benchmark that instead
+	if ethash.shared != nil {
+		return ethash.shared.benchmark(algorithm, duration)
+	}
+	threads := ethash.Threads()
+	if threads <= 0 {
+		threads = runtime.NumCPU()
+	}
+	var (
+		cache  = ethash.cache(0)
+		size   = datasetSize(0)
+		hash   = make([]byte, common.HashLength)
+		hashes atomic.Uint64
+		pend   sync.WaitGroup
+	)
+	if ethash.config.PowMode == ModeTest {
+		size = 32 * 1024
+	}
+	crand.Read(hash)
+
+	start := time.Now()
+	deadline := start.Add(duration)
+	for i := 0; i < threads; i++ {
+		pend.Add(1)
+		go func(nonce uint64) {
+			defer pend.Done()
+
+			for attempts := uint64(0); ; attempts++ {
+				// Don't check the clock on every nonce, only after 2^X nonces
+				if attempts%(1<<10) == 0 && time.Now().After(deadline) {
+					hashes.Add(attempts)
+					return
+				}
+				hashimotoLight(size, cache.cache, hash, nonce)
+				nonce++
+			}
+		}(uint64(i) << 32)
+	}
+	pend.Wait()
+
+	// Caches are unmapped in a finalizer. Ensure that the cache stays live
+	// during the benchmark so it's not unmapped while being read.
+	runtime.KeepAlive(cache)
+
+	return uint64(float64(hashes.Load()) / time.Since(start).Seconds()), nil
+}
+
 // This is the timeout for HTTP requests to notify external miners.
 const remoteSealerTimeout = 1 * time.Second
 
@@ -18,6 +18,7 @@ package ethash
 
 import (
 	"encoding/json"
+	"errors"
 	"io"
 	"math/big"
 	"net/http"
@@ -296,3 +297,31 @@ func TestStaleSubmission(t *testing.T) {
 		}
 	}
 }
+
+// Tests that the hashrate benchmark runs the local nonce search.
+func TestBenchmark(t *testing.T) {
+	ethash := NewTester(nil, false)
+	defer ethash.Close()
+
+	api := &MinerAPI{ethash: ethash}
+	rate, err := api.Benchmark(types.EthashAlgorithm, 1)
+	if err != nil {
+		t.Fatalf("failed to benchmark ethash: %v", err)
+	}
+	if rate == 0 {
+		t.Errorf("benchmark hashrate mismatch: have %d, want positive", rate)
+	}
+	if _, err := api.Benchmark(types.Sha256Algorithm, 1); err != errUnsupportedAlgorithm {
+		t.Errorf("sha256 benchmark error mismatch: have %v, want %v", err, errUnsupportedAlgorithm)
+	}
+	for _, seconds := range []uint64{0, maxBenchmarkSeconds + 1} {
+		if _, err := api.Benchmark(types.EthashAlgorithm, seconds); !errors.Is(err, errBenchmarkDuration) {
+			t.Errorf("%d second benchmark error mismatch: have %v, want %v", seconds, err, errBenchmarkDuration)
+		}
+	}
+	// The benchmark must not generate the mining dataset on demand
+	current, _ := ethash.datasets.get(0)
+	if current.generated() {
+		t.Errorf("benchmark generated the mining dataset")
+	}
+}
@@ -62,7 +62,13 @@ func CalcBaseFee(config *params.ChainConfig, parent *types.Header) *big.Int {
 		return CalcPoSBaseFee(config, parent)
 	}
 
-	// If the difficulty is >= CanxiumInitialBaseFeeDifficulty (1P), return zero
+	// A zero (or missing) difficulty can only come from a malformed header, don't
+	// let it inflate the base fee to the maximum.
+	if parent.Difficulty == nil || parent.Difficulty.Sign() <= 0 {
+		return initialBaseFee
+	}
+	// If the difficulty is >= CanxiumInitialBaseFeeDifficulty (1P), return the
+	// initial base fee, the subtraction below would otherwise go negative.
 	if parent.Difficulty.Cmp(params.CanxiumInitialBaseFeeDifficulty) >= 0 {
 		return initialBaseFee
 	}
@@ -129,3 +129,38 @@ func TestCalcBaseFee(t *testing.T) {
 		}
 	}
 }
+
+// TestCalcCanxiumBaseFee checks the difficulty based base fee of the canxium chain.
+func TestCalcCanxiumBaseFee(t *testing.T) {
+	config := config()
+	config.CanxiumBlock = big.NewInt(0)
+
+	var (
+		initial    = big.NewInt(params.InitialBaseFee)
+		maxDiff    = params.CanxiumInitialBaseFeeDifficulty
+		belowDiff  = new(big.Int).Sub(maxDiff, new(big.Int).Mul(params.Big100Kh, big.NewInt(3e9)))
+		doubleDiff = new(big.Int).Mul(maxDiff, big.NewInt(2))
+	)
+	tests := []struct {
+		difficulty *big.Int
+		expected   *big.Int
+	}{
+		{nil, initial},                            // missing difficulty
+		{new(big.Int), initial},                   // zero difficulty
+		{big.NewInt(-1), initial},                 // negative difficulty
+		{maxDiff, initial},                        // at the initial difficulty
+		{doubleDiff, initial},                     // above the initial difficulty
+		{belowDiff, big.NewInt(6e9)},              // 3e9 * 100KH below the initial difficulty
+		{big.NewInt(1), big.NewInt(199999999998)}, // lowest possible difficulty
+	}
+	for i, test := range tests {
+		parent := &types.Header{
+			Number:     common.Big32,
+			GasLimit:   20000000,
+			Difficulty: test.difficulty,
+		}
+		if have := CalcBaseFee(config, parent); have.Cmp(test.expected) != 0 {
+			t.Errorf("test %d: have %d  want %d", i, have, test.expected)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+// Copyright 2023 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package misc
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// ErrInvalidMiningTxValue is returned if the value of an offline mining
+// transaction doesn't match the mining subsidy times the transaction difficulty.
+var ErrInvalidMiningTxValue = errors.New("invalid mining transaction value")
+
+// MiningSubsidy returns the offline mining subsidy per difficulty hash of the
+// given algorithm at the given block.
+type MiningSubsidy func(config *params.ChainConfig, block *big.Int, algorithm uint8) *big.Int
+
+// VerifyMiningTxValue verifies that the value of an offline mining transaction
+// equals the mining subsidy at the given block times the transaction difficulty.
+// Once the mining reward grace fork is active, transactions paying the subsidy
+// of the block MiningRewardGraceWindow blocks earlier are accepted too, so that
+// transactions built right before a subsidy reduction aren't dropped. Once the
+// mining reward waiver fork is active, transactions waiving the reward are
+// accepted too.
+func VerifyMiningTxValue(config *params.ChainConfig, subsidy MiningSubsidy, block *big.Int, tx *types.Transaction) error {
+	if config.IsMiningRewardWaiver(block) && tx.Value().Sign() == 0 {
+		return nil
+	}
+	value := new(big.Int).Mul(subsidy(config, block, tx.Algorithm()), tx.Difficulty())
+	if tx.Value().Cmp(value) == 0 {
+		return nil
+	}
+	if config.IsMiningRewardGrace(block) && config.MiningRewardGraceWindow > 0 {
+		prev := new(big.Int).Sub(block, new(big.Int).SetUint64(config.MiningRewardGraceWindow))
+		if config.IsHydro(prev) {
+			prevValue := new(big.Int).Mul(subsidy(config, prev, tx.Algorithm()), tx.Difficulty())
+			if tx.Value().Cmp(prevValue) == 0 {
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf("%w: have %v, want %v", ErrInvalidMiningTxValue, tx.Value(), value)
+}
@@ -0,0 +1,95 @@
+// Copyright 2023 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package misc
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// testMiningSubsidy halves the subsidy every 100 blocks.
+func testMiningSubsidy(config *params.ChainConfig, block *big.Int, algorithm uint8) *big.Int {
+	return new(big.Int).Rsh(big.NewInt(1000), uint(block.Uint64()/100))
+}
+
+func TestVerifyMiningTxValue(t *testing.T) {
+	var (
+		difficulty = big.NewInt(10)
+		oldValue   = big.NewInt(10000) // subsidy of the first period
+		newValue   = big.NewInt(5000)  // subsidy of the second period
+	)
+	graceConfig := func(fork *big.Int, window uint64) *params.ChainConfig {
+		config := *params.TestChainConfig
+		config.HydroBlock = big.NewInt(0)
+		config.MiningRewardGraceBlock = fork
+		config.MiningRewardGraceWindow = window
+		return &config
+	}
+	waiverConfig := func(fork *big.Int) *params.ChainConfig {
+		config := graceConfig(nil, 0)
+		config.MiningRewardWaiverBlock = fork
+		return config
+	}
+
+	tests := []struct {
+		config *params.ChainConfig
+		block  int64
+		value  *big.Int
+		ok     bool
+	}{
+		// Without the grace fork only the current subsidy is accepted
+		{graceConfig(nil, 10), 99, oldValue, true},
+		{graceConfig(nil, 10), 100, oldValue, false},
+		{graceConfig(nil, 10), 100, newValue, true},
+		// Within the grace window both subsidies are accepted
+		{graceConfig(big.NewInt(0), 10), 100, oldValue, true},
+		{graceConfig(big.NewInt(0), 10), 109, oldValue, true},
+		{graceConfig(big.NewInt(0), 10), 109, newValue, true},
+		// Outside of the grace window only the current subsidy is accepted
+		{graceConfig(big.NewInt(0), 10), 110, oldValue, false},
+		{graceConfig(big.NewInt(0), 10), 110, newValue, true},
+		// Grace fork not yet active
+		{graceConfig(big.NewInt(101), 10), 100, oldValue, false},
+		// Zero window disables the grace rule
+		{graceConfig(big.NewInt(0), 0), 100, oldValue, false},
+		// Values matching neither period are rejected
+		{graceConfig(big.NewInt(0), 10), 105, big.NewInt(7500), false},
+		// Zero values are only accepted with the reward waiver
+		{graceConfig(nil, 0), 100, new(big.Int), false},
+		{waiverConfig(big.NewInt(100)), 99, new(big.Int), false},
+		{waiverConfig(big.NewInt(100)), 100, new(big.Int), true},
+		{waiverConfig(big.NewInt(100)), 100, big.NewInt(1), false},
+	}
+	for i, test := range tests {
+		tx := types.NewTx(&types.MiningTx{
+			Value:      test.value,
+			Algorithm:  types.EthashAlgorithm,
+			Difficulty: difficulty,
+		})
+		err := VerifyMiningTxValue(test.config, testMiningSubsidy, big.NewInt(test.block), tx)
+		if test.ok && err != nil {
+			t.Errorf("test %d: unexpected error: %v", i, err)
+		}
+		if !test.ok && !errors.Is(err, ErrInvalidMiningTxValue) {
+			t.Errorf("test %d: error mismatch: have %v, want %v", i, err, ErrInvalidMiningTxValue)
+		}
+	}
+}
@@ -116,6 +116,35 @@ func (v *BlockValidator) ValidateState(block *types.Block, statedb *state.StateD
 	return nil
 }
 
+// ValidateBlockMiningTxs validates the mining transactions of the given block.
+// The block level limits are checked once for the whole block before the seal
+// of every mining transaction is verified by the consensus engine.
+func ValidateBlockMiningTxs(engine consensus.Engine, config *params.ChainConfig, block *types.Block) error {
+	var count int64
+	for _, tx := range block.Transactions() {
+		if tx.IsMiningTx() {
+			count++
+		}
+	}
+	if count == 0 {
+		return nil
+	}
+	if !config.IsHydro(block.Number()) {
+		return fmt.Errorf("%w: have %d mining transactions", ErrMiningTxBeforeHydro, count)
+	}
+	if count > MaxMiningTransactionPerBlock {
+		return fmt.Errorf("%w: have %d mining transactions, max %d", ErrBadMiningTxs, count, MaxMiningTransactionPerBlock)
+	}
+	txSealCh := engine.VerifyTxsSeal(config, block.Transactions(), block.Number(), false)
+	if txSealCh == nil {
+		return errInvalidEngine
+	}
+	if <-txSealCh < 0 {
+		return ErrBadMiningTxs
+	}
+	return nil
+}
+
 // CalcGasLimit computes the gas limit of the next block after parent. It aims
 // to keep the baseline gas close to the provided target, and increase it towards
 // the target if the baseline gas is lower.
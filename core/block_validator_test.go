@@ -17,6 +17,7 @@
 package core
 
 import (
+	"errors"
 	"math/big"
 	"runtime"
 	"testing"
@@ -32,6 +33,7 @@ import (
 	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/trie"
 )
 
 // Tests that simple header verification works, for both good and bad blocks.
@@ -389,3 +391,84 @@ func TestCalcGasLimit(t *testing.T) {
 		}
 	}
 }
+
+// Tests that the block level mining transaction limits are enforced even if every
+// mining transaction of the block is individually valid.
+func TestValidateBlockMiningTxs(t *testing.T) {
+	var (
+		engine = ethash.NewFaker()
+		config = *params.TestChainConfig
+		header = &types.Header{Number: big.NewInt(1)}
+	)
+	config.HydroBlock = big.NewInt(0)
+
+	miningTxs := func(n int) types.Transactions {
+		txs := make(types.Transactions, n)
+		for i := range txs {
+			txs[i] = types.NewTx(&types.MiningTx{
+				Nonce:      uint64(i),
+				To:         config.MiningContract,
+				Algorithm:  types.EthashAlgorithm,
+				Difficulty: big.NewInt(1),
+			})
+		}
+		return txs
+	}
+	tests := []struct {
+		txs   types.Transactions
+		valid bool
+	}{
+		{nil, true},
+		{types.Transactions{types.NewTransaction(0, common.Address{}, big.NewInt(1), params.TxGas, nil, nil)}, true},
+		{miningTxs(1), true},
+		{miningTxs(MaxMiningTransactionPerBlock), true},
+		{miningTxs(MaxMiningTransactionPerBlock + 1), false},
+	}
+	for i, test := range tests {
+		for j, tx := range test.txs {
+			if err := engine.VerifyTxSeal(&config, tx, header.Number, false); tx.IsMiningTx() && err != nil {
+				t.Fatalf("test %d: transaction %d individually invalid: %v", i, j, err)
+			}
+		}
+		block := types.NewBlock(header, test.txs, nil, nil, trie.NewStackTrie(nil))
+		err := ValidateBlockMiningTxs(engine, &config, block)
+		if test.valid && err != nil {
+			t.Errorf("test %d: unexpected error: %v", i, err)
+		}
+		if !test.valid && !errors.Is(err, ErrBadMiningTxs) {
+			t.Errorf("test %d: error mismatch: have %v, want %v", i, err, ErrBadMiningTxs)
+		}
+	}
+}
+
+// Tests that blocks containing mining transactions are rejected before the hydro
+// fork and accepted from the hydro fork block onwards.
+func TestValidateBlockMiningTxsHydro(t *testing.T) {
+	var (
+		engine = ethash.NewFaker()
+		config = *params.TestChainConfig
+		txs    = types.Transactions{types.NewTx(&types.MiningTx{
+			To:         config.MiningContract,
+			Algorithm:  types.EthashAlgorithm,
+			Difficulty: big.NewInt(1),
+		})}
+	)
+	config.HydroBlock = big.NewInt(10)
+
+	tests := []struct {
+		number int64
+		txs    types.Transactions
+		err    error
+	}{
+		{9, nil, nil},
+		{9, txs, ErrMiningTxBeforeHydro},
+		{10, txs, nil},
+		{11, txs, nil},
+	}
+	for i, test := range tests {
+		block := types.NewBlock(&types.Header{Number: big.NewInt(test.number)}, test.txs, nil, nil, trie.NewStackTrie(nil))
+		if err := ValidateBlockMiningTxs(engine, &config, block); !errors.Is(err, test.err) {
+			t.Errorf("test %d: error mismatch: have %v, want %v", i, err, test.err)
+		}
+	}
+}
@@ -1756,17 +1756,10 @@ func (bc *BlockChain) insertChain(chain types.Blocks, verifySeals, setHead bool)
 
 		// Before insert block to the chain, make sure all mining transaction are valid
 		// This type of transaction requires special verification steps, which cannot be verified by conventional methods.
-		txSealCh := bc.engine.VerifyTxsSeal(bc.chainConfig, block.Transactions(), block.Number(), false)
-		if txSealCh == nil {
-			return it.index, errInvalidEngine
-		}
-
-		numValidMiningTxs := <-txSealCh
-		if numValidMiningTxs < 0 || numValidMiningTxs > MaxMiningTransactionPerBlock {
-			// one of txs seal return error or more than MaxMiningTransactionPerBlock, abort this block
-			log.Warn("Found a bad block because of malicious mining transactions", "hash", block.Hash(), "miningTxs", numValidMiningTxs, "max", MaxMiningTransactionPerBlock)
-			bc.reportBlock(block, nil, ErrBadMiningTxs)
-			return it.index, ErrBadMiningTxs
+		if err := ValidateBlockMiningTxs(bc.engine, bc.chainConfig, block); err != nil {
+			log.Warn("Found a bad block because of malicious mining transactions", "hash", block.Hash(), "err", err)
+			bc.reportBlock(block, nil, err)
+			return it.index, err
 		}
 
 		// Process block using the parent state as reference point
@@ -18,6 +18,7 @@ package core
 
 import (
 	"errors"
+	"fmt"
 
 	"github.com/ethereum/go-ethereum/core/types"
 )
@@ -32,6 +33,11 @@ var (
 	// ErrBannedHash is returned if a block to import is on the banned list.
 	ErrBadMiningTxs = errors.New("found a malicious mining transaction")
 
+	// ErrMiningTxBeforeHydro is returned if a block to import or the transaction
+	// pool contains mining transactions before the hydro fork activated offline
+	// mining. It wraps ErrTxTypeNotSupported.
+	ErrMiningTxBeforeHydro = fmt.Errorf("%w: mining transaction before hydro fork", types.ErrTxTypeNotSupported)
+
 	// ErrNoGenesis is returned when there is no Genesis Block.
 	ErrNoGenesis = errors.New("genesis not found in chain")
 
@@ -296,6 +296,7 @@ func SetupGenesisBlockWithOverride(db ethdb.Database, triedb *trie.Database, gen
 			if overrides != nil && overrides.OverrideShanghai != nil {
 				config.ShanghaiTime = overrides.OverrideShanghai
 			}
+			deriveMiningContract(config)
 		}
 	}
 	// Just commit the new block if there is no stored genesis block.
@@ -415,6 +416,16 @@ func LoadCliqueConfig(db ethdb.Database, genesis *Genesis) (*params.CliqueConfig
 	return nil, nil
 }
 
+// deriveMiningContract sets the mining contract address to the contract created
+// by the configured deployer account, if the address isn't configured explicitly.
+func deriveMiningContract(config *params.ChainConfig) {
+	if config.MiningContract != (common.Address{}) || config.MiningContractDeployer == nil {
+		return
+	}
+	deployer := config.MiningContractDeployer
+	config.MiningContract = crypto.CreateAddress(deployer.Address, deployer.Nonce)
+}
+
 func (g *Genesis) configOrDefault(ghash common.Hash) *params.ChainConfig {
 	switch {
 	case g != nil:
@@ -486,6 +497,7 @@ func (g *Genesis) Commit(db ethdb.Database, triedb *trie.Database) (*types.Block
 	if config == nil {
 		config = params.AllEthashProtocolChanges
 	}
+	deriveMiningContract(config)
 	if err := config.CheckConfigForkOrder(); err != nil {
 		return nil, err
 	}
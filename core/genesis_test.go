@@ -243,3 +243,41 @@ func TestReadWriteGenesisAlloc(t *testing.T) {
 		}
 	}
 }
+
+// Tests that the mining contract address is derived from the configured deployer
+// when it isn't set explicitly, and that the derived address is persisted.
+func TestSetupGenesisMiningContract(t *testing.T) {
+	var (
+		deployer = &params.MiningContractDeployerConfig{
+			Address: common.HexToAddress("0x970e8128ab834e8eac17ab8e3812f010678cf791"),
+			Nonce:   0,
+		}
+		derived  = common.HexToAddress("0x333c3310824b7c685133f2bedb2ca4b8b4df633d")
+		explicit = common.HexToAddress("0x6c6f6c6c6f6c6c6f6c6c6f6c6c6f6c6c6f6c6c6f")
+	)
+	tests := []struct {
+		contract common.Address
+		want     common.Address
+	}{
+		{common.Address{}, derived},
+		{explicit, explicit},
+	}
+	for i, test := range tests {
+		config := *params.TestChainConfig
+		config.MiningContract = test.contract
+		config.MiningContractDeployer = deployer
+
+		db := rawdb.NewMemoryDatabase()
+		genesis := &Genesis{Config: &config, BaseFee: big.NewInt(params.InitialBaseFee)}
+		newcfg, hash, err := SetupGenesisBlock(db, trie.NewDatabase(db), genesis)
+		if err != nil {
+			t.Fatalf("test %d: failed to setup genesis: %v", i, err)
+		}
+		if newcfg.MiningContract != test.want {
+			t.Errorf("test %d: mining contract mismatch: have %x, want %x", i, newcfg.MiningContract, test.want)
+		}
+		if stored := rawdb.ReadChainConfig(db, hash); stored.MiningContract != test.want {
+			t.Errorf("test %d: stored mining contract mismatch: have %x, want %x", i, stored.MiningContract, test.want)
+		}
+	}
+}
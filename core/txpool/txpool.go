@@ -106,6 +106,14 @@ var (
 	// ErrUnderDifficulty is returned if a mining transaction's difficulty is below the minimum
 	// configured for the offline mining consensus.
 	ErrDifficultyUnderValue = errors.New("mining transaction difficulty under value")
+
+	// ErrUnsupportedMiningAlgorithm is returned if a mining transaction declares a
+	// mining algorithm the consensus engine can't verify.
+	ErrUnsupportedMiningAlgorithm = errors.New("unsupported mining algorithm")
+
+	// ErrMiningTxGasTooLow is returned if a mining transaction's gas limit is below
+	// the mining gas floor configured for the transaction pool.
+	ErrMiningTxGasTooLow = errors.New("mining transaction gas too low")
 )
 
 var (
@@ -182,6 +190,8 @@ type Config struct {
 	PriceLimit uint64 // Minimum gas price to enforce for acceptance into the pool
 	PriceBump  uint64 // Minimum price bump percentage to replace an already existing transaction (nonce)
 
+	MiningGasFloor uint64 // Minimum gas limit to enforce for mining transactions once the hydro fork activates (0 = disabled)
+
 	AccountSlots uint64 // Number of executable transaction slots guaranteed per account
 	GlobalSlots  uint64 // Maximum number of executable transaction slots for all accounts
 	AccountQueue uint64 // Maximum number of non-executable transaction slots permitted per account
@@ -648,7 +658,7 @@ func (pool *TxPool) validateTxBasics(tx *types.Transaction, local bool) error {
 	}
 	// Reject mining transaction until Hydro fork activates.
 	if !pool.hydro.Load() && tx.Type() == types.MiningTxType {
-		return core.ErrTxTypeNotSupported
+		return core.ErrMiningTxBeforeHydro
 	}
 	// Reject transactions over defined size to prevent DOS attacks
 	if tx.Size() > txMaxSize {
@@ -698,6 +708,17 @@ func (pool *TxPool) validateTxBasics(tx *types.Transaction, local bool) error {
 	}
 
 	if tx.Type() == types.MiningTxType {
+		// Mining transactions are gas free, so the floor doesn't make them more
+		// expensive. It only makes every admitted mining transaction reserve at
+		// least that much block gas, limiting how many fit into a block.
+		if tx.Gas() < pool.config.MiningGasFloor {
+			return fmt.Errorf("%w: have %d, want %d", ErrMiningTxGasTooLow, tx.Gas(), pool.config.MiningGasFloor)
+		}
+		// Only ethash mining transactions can be verified. Reject the others even
+		// before the mining algorithm fork, as they'd become invalid with it.
+		if tx.Algorithm() != types.EthashAlgorithm {
+			return fmt.Errorf("%w: %d", ErrUnsupportedMiningAlgorithm, tx.Algorithm())
+		}
 		// Ensure destination have to be the mining contract
 		if tx.To() == nil || *tx.To() != pool.chainconfig.MiningContract {
 			return ErrInvalidMiningReceiver
@@ -1724,9 +1745,7 @@ func (pool *TxPool) demoteUnexecutables() {
 
 // Check if the mining transaction has correct value, mining rewards will be reduced every month
 func (pool *TxPool) isValidMiningSubsidy(headNumber *big.Int, tx *types.Transaction) bool {
-	subsidy := pool.engine.TransactionMiningSubsidy(pool.chainconfig, headNumber)
-	value := new(big.Int).Mul(subsidy, tx.Difficulty())
-	return tx.Value().Cmp(value) == 0
+	return misc.VerifyMiningTxValue(pool.chainconfig, pool.engine.TransactionMiningSubsidy, headNumber, tx) == nil
 }
 
 // addressByHeartbeat is an account address tagged with its last activity timestamp.
@@ -122,6 +122,23 @@ func dynamicFeeTx(nonce uint64, gaslimit uint64, gasFee *big.Int, tip *big.Int,
 	return tx
 }
 
+func miningTransaction(config *params.ChainConfig, nonce uint64, gaslimit uint64, key *ecdsa.PrivateKey) *types.Transaction {
+	difficulty := big.NewInt(1)
+	tx, _ := types.SignNewTx(key, types.LatestSignerForChainID(config.ChainID), &types.MiningTx{
+		ChainID:    config.ChainID,
+		Nonce:      nonce,
+		GasTipCap:  new(big.Int),
+		GasFeeCap:  new(big.Int),
+		Gas:        gaslimit,
+		From:       crypto.PubkeyToAddress(key.PublicKey),
+		To:         config.MiningContract,
+		Value:      new(big.Int).Mul(ethash.CanxiumMaxTransactionReward, difficulty),
+		Algorithm:  types.EthashAlgorithm,
+		Difficulty: difficulty,
+	})
+	return tx
+}
+
 func setupPool() (*TxPool, *ecdsa.PrivateKey) {
 	return setupPoolWithConfig(params.TestChainConfig)
 }
@@ -323,6 +340,108 @@ func TestInvalidTransactions(t *testing.T) {
 	}
 }
 
+// Tests that mining transactions are only accepted once the pending block is
+// past the hydro fork.
+func TestMiningTransactionHydro(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		hydro int64
+		err   error
+	}{
+		{2, core.ErrMiningTxBeforeHydro}, // pending block 1 is before the fork
+		{1, nil},                         // pending block 1 is the fork block
+		{0, nil},
+	}
+	for i, test := range tests {
+		config := *params.TestChainConfig
+		config.HydroBlock = big.NewInt(test.hydro)
+
+		pool, key := setupPoolWithConfig(&config)
+		err := pool.AddRemote(miningTransaction(&config, 0, params.TxGas, key))
+		if !errors.Is(err, test.err) {
+			t.Errorf("test %d: error mismatch: have %v, want %v", i, err, test.err)
+		}
+		// Callers matching the generic unsupported type error keep working
+		if test.err != nil && !errors.Is(err, core.ErrTxTypeNotSupported) {
+			t.Errorf("test %d: error %v doesn't wrap %v", i, err, core.ErrTxTypeNotSupported)
+		}
+		pool.Stop()
+	}
+}
+
+// Tests that the pool only admits mining transactions of algorithms the engine
+// can verify, both before and after the mining algorithm fork.
+func TestMiningTransactionAlgorithm(t *testing.T) {
+	t.Parallel()
+
+	for _, fork := range []int64{0, 100} {
+		config := *params.TestChainConfig
+		config.HydroBlock = big.NewInt(0)
+		config.MiningAlgorithmBlock = big.NewInt(fork)
+
+		pool, key := setupPoolWithConfig(&config)
+		for i, algorithm := range []uint8{types.EthashAlgorithm, types.NoneAlgorithm, types.Sha256Algorithm} {
+			tx, _ := types.SignNewTx(key, types.LatestSignerForChainID(config.ChainID), &types.MiningTx{
+				ChainID:    config.ChainID,
+				Nonce:      uint64(i),
+				GasTipCap:  new(big.Int),
+				GasFeeCap:  new(big.Int),
+				Gas:        params.TxGas,
+				From:       crypto.PubkeyToAddress(key.PublicKey),
+				To:         config.MiningContract,
+				Value:      ethash.CanxiumMaxTransactionReward,
+				Algorithm:  algorithm,
+				Difficulty: big.NewInt(1),
+			})
+			err := pool.AddRemote(tx)
+			if algorithm == types.EthashAlgorithm && err != nil {
+				t.Errorf("fork %d, algorithm %d: unexpected error: %v", fork, algorithm, err)
+			}
+			if algorithm != types.EthashAlgorithm && !errors.Is(err, ErrUnsupportedMiningAlgorithm) {
+				t.Errorf("fork %d, algorithm %d: error mismatch: have %v, want %v", fork, algorithm, err, ErrUnsupportedMiningAlgorithm)
+			}
+		}
+		pool.Stop()
+	}
+}
+
+// Tests that mining transactions below the configured mining gas floor are
+// rejected by the pool, and that no floor is enforced unless configured.
+func TestMiningTransactionGasFloor(t *testing.T) {
+	t.Parallel()
+
+	hydro := *params.TestChainConfig
+	hydro.HydroBlock = big.NewInt(0)
+
+	// Without a configured floor, any gas limit covering the intrinsic gas is fine
+	pool, key := setupPoolWithConfig(&hydro)
+	if err := pool.AddRemote(miningTransaction(&hydro, 0, params.TxGas, key)); err != nil {
+		t.Errorf("no floor: unexpected error: %v", err)
+	}
+	pool.Stop()
+
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	blockchain := newTestBlockChain(10000000, statedb, new(event.Feed))
+
+	config := testTxPoolConfig
+	config.MiningGasFloor = 50000
+	pool = NewTxPool(config, &hydro, blockchain, ethash.NewFaker())
+	<-pool.initDoneCh
+	defer pool.Stop()
+
+	key, _ = crypto.GenerateKey()
+	if err, want := pool.AddRemote(miningTransaction(&hydro, 0, config.MiningGasFloor-1, key)), ErrMiningTxGasTooLow; !errors.Is(err, want) {
+		t.Errorf("below floor: want %v have %v", want, err)
+	}
+	if err := pool.AddRemote(miningTransaction(&hydro, 0, config.MiningGasFloor, key)); err != nil {
+		t.Errorf("at floor: unexpected error: %v", err)
+	}
+	if err := pool.AddRemote(miningTransaction(&hydro, 1, config.MiningGasFloor+1, key)); err != nil {
+		t.Errorf("above floor: unexpected error: %v", err)
+	}
+}
+
 func TestQueue(t *testing.T) {
 	t.Parallel()
 
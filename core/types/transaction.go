@@ -20,6 +20,7 @@ import (
 	"bytes"
 	"container/heap"
 	"errors"
+	"fmt"
 	"io"
 	"math/big"
 	"sync/atomic"
@@ -37,6 +38,8 @@ var (
 	ErrInvalidTxType        = errors.New("transaction type not valid in this context")
 	ErrTxTypeNotSupported   = errors.New("transaction type not supported")
 	ErrGasFeeCapTooLow      = errors.New("fee cap less than base fee")
+	ErrMiningTxAccessList   = errors.New("mining transaction does not support access lists")
+	ErrMiningTxFromMismatch = errors.New("mining transaction from does not match signer")
 	errShortTypedTx         = errors.New("typed transaction too short")
 )
 
@@ -194,7 +197,36 @@ func (tx *Transaction) UnmarshalBinary(b []byte) error {
 	return nil
 }
 
-// decodeTyped decodes a typed transaction from the canonical format.
+// UnmarshalBinaryStrict decodes the canonical encoding of transactions like
+// UnmarshalBinary, additionally checking that a signed mining transaction's
+// From field matches the address recovered from its signature. Unsigned
+// mining transactions and other transaction types are decoded unchanged.
+func (tx *Transaction) UnmarshalBinaryStrict(b []byte, signer Signer) error {
+	var decoded Transaction
+	if err := decoded.UnmarshalBinary(b); err != nil {
+		return err
+	}
+	if decoded.Type() == MiningTxType {
+		v, r, s := decoded.RawSignatureValues()
+		if v.Sign() != 0 || r.Sign() != 0 || s.Sign() != 0 {
+			from, err := Sender(signer, &decoded)
+			if err != nil {
+				return err
+			}
+			if from != decoded.From() {
+				return fmt.Errorf("%w: have %v, want %v", ErrMiningTxFromMismatch, decoded.From(), from)
+			}
+		}
+	}
+	tx.setDecoded(decoded.inner, uint64(len(b)))
+	if sc := decoded.from.Load(); sc != nil {
+		tx.from.Store(sc)
+	}
+	return nil
+}
+
+// decodeTyped decodes a typed transaction from the canonical format. The payload
+// must be fully consumed, trailing bytes are rejected with rlp.ErrMoreThanOneValue.
 func (tx *Transaction) decodeTyped(b []byte) (TxData, error) {
 	if len(b) <= 1 {
 		return nil, errShortTypedTx
@@ -600,6 +632,12 @@ func (s TxByPriceAndTime) Less(i, j int) bool {
 	// deterministic sorting
 	cmp := s[i].minerFee.Cmp(s[j].minerFee)
 	if cmp == 0 {
+		// Mining transactions paying the same reward prefer the higher difficulty
+		if s[i].tx.IsMiningTx() && s[j].tx.IsMiningTx() {
+			if cmp := s[i].tx.Difficulty().Cmp(s[j].tx.Difficulty()); cmp != 0 {
+				return cmp > 0
+			}
+		}
 		return s[i].tx.time.Before(s[j].tx.time)
 	}
 	return cmp > 0
@@ -295,7 +295,10 @@ func (tx *Transaction) UnmarshalJSON(input []byte) error {
 	case MiningTxType:
 		var itx MiningTx
 		inner = &itx
-		// Access list is optional for now.
+		// Mining transactions have no access list, reject rather than drop one.
+		if dec.AccessList != nil && len(*dec.AccessList) > 0 {
+			return ErrMiningTxAccessList
+		}
 		if dec.ChainID == nil {
 			return errors.New("missing required field 'chainId' in transaction")
 		}
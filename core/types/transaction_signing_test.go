@@ -136,3 +136,121 @@ func TestChainId(t *testing.T) {
 		t.Error("expected no error")
 	}
 }
+
+// countingSigner wraps a signer and counts the number of signature recoveries.
+type countingSigner struct {
+	Signer
+	recoveries *int
+}
+
+func (s countingSigner) Sender(tx *Transaction) (common.Address, error) {
+	*s.recoveries++
+	return s.Signer.Sender(tx)
+}
+
+func (s countingSigner) Equal(s2 Signer) bool {
+	x, ok := s2.(countingSigner)
+	return ok && x.recoveries == s.recoveries && s.Signer.Equal(x.Signer)
+}
+
+func newTestMiningTx(from common.Address) *MiningTx {
+	return &MiningTx{
+		ChainID:    big.NewInt(1),
+		Nonce:      1,
+		GasTipCap:  new(big.Int),
+		GasFeeCap:  new(big.Int),
+		Gas:        100000,
+		From:       from,
+		To:         common.HexToAddress("0x6c6f6c6c6f6c6c6f6c6c6f6c6c6f6c6c6f6c6c6f"),
+		Value:      big.NewInt(4250),
+		Data:       common.FromHex("eedc3c83000000000000000000000000"),
+		Algorithm:  EthashAlgorithm,
+		Difficulty: big.NewInt(1),
+	}
+}
+
+func TestMiningTxSenderCache(t *testing.T) {
+	key, addr := defaultTestKey()
+
+	var (
+		count  int
+		signer = countingSigner{NewLondonSigner(big.NewInt(1)), &count}
+	)
+	tx, err := SignNewTx(key, signer, newTestMiningTx(addr))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 5; i++ {
+		from, err := Sender(signer, tx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if from != addr {
+			t.Fatalf("sender mismatch: have %x, want %x", from, addr)
+		}
+	}
+	if count != 1 {
+		t.Errorf("signature recovered %d times, want 1", count)
+	}
+	// Changing the signature values produces a new transaction, which must not
+	// inherit the sender cached on the original one.
+	otherKey, _ := crypto.GenerateKey()
+	otherAddr := crypto.PubkeyToAddress(otherKey.PublicKey)
+
+	h := signer.Hash(tx)
+	sig, err := crypto.Sign(h[:], otherKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resigned, err := tx.WithSignature(signer, sig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	from, err := Sender(signer, resigned)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if from != otherAddr {
+		t.Errorf("resigned sender mismatch: have %x, want %x", from, otherAddr)
+	}
+	if count != 2 {
+		t.Errorf("signature recovered %d times, want 2", count)
+	}
+}
+
+func BenchmarkMiningTxSender(b *testing.B) {
+	key, addr := defaultTestKey()
+	tx := MustSignNewTx(key, NewLondonSigner(big.NewInt(1)), newTestMiningTx(addr))
+
+	// Each iteration validates the transaction 3 times, mimicking the pool,
+	// block building and block import.
+	b.Run("uncached", func(b *testing.B) {
+		var (
+			count  int
+			signer = countingSigner{NewLondonSigner(big.NewInt(1)), &count}
+		)
+		for i := 0; i < b.N; i++ {
+			for j := 0; j < 3; j++ {
+				if _, err := signer.Sender(tx); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+		b.ReportMetric(float64(count)/float64(b.N), "recoveries/op")
+	})
+	b.Run("cached", func(b *testing.B) {
+		var (
+			count  int
+			signer = countingSigner{NewLondonSigner(big.NewInt(1)), &count}
+		)
+		for i := 0; i < b.N; i++ {
+			cpy := &Transaction{inner: tx.inner}
+			for j := 0; j < 3; j++ {
+				if _, err := Sender(signer, cpy); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+		b.ReportMetric(float64(count)/float64(b.N), "recoveries/op")
+	})
+}
@@ -82,6 +82,44 @@ func TestDecodeEmptyTypedTx(t *testing.T) {
 	}
 }
 
+// Tests that typed transactions followed by trailing bytes are rejected rather
+// than decoding to the same transaction as the canonical encoding.
+func TestDecodeTypedTxTrailingBytes(t *testing.T) {
+	key, addr := defaultTestKey()
+	recipient := common.HexToAddress("095e7baea6a6c7c4c2dfeb977efac326af552d87")
+
+	for _, txdata := range []TxData{
+		&AccessListTx{ChainID: big.NewInt(1), To: &recipient, Gas: 21000, GasPrice: big.NewInt(1)},
+		&DynamicFeeTx{ChainID: big.NewInt(1), To: &recipient, Gas: 21000, GasTipCap: big.NewInt(1), GasFeeCap: big.NewInt(1)},
+		newTestMiningTx(addr),
+	} {
+		tx, err := SignNewTx(key, NewLondonSigner(big.NewInt(1)), txdata)
+		if err != nil {
+			t.Fatal(err)
+		}
+		blob, err := tx.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		padded := append(blob, 0x00)
+
+		var binary Transaction
+		if err := binary.UnmarshalBinary(padded); err != rlp.ErrMoreThanOneValue {
+			t.Errorf("type %d: binary decoding error mismatch: have %v, want %v", tx.Type(), err, rlp.ErrMoreThanOneValue)
+		}
+		// Typed transactions embedded in blocks and network messages are wrapped
+		// into an RLP string, which goes through DecodeRLP instead.
+		enc, err := rlp.EncodeToBytes(padded)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var envelope Transaction
+		if err := rlp.DecodeBytes(enc, &envelope); err != rlp.ErrMoreThanOneValue {
+			t.Errorf("type %d: envelope decoding error mismatch: have %v, want %v", tx.Type(), err, rlp.ErrMoreThanOneValue)
+		}
+	}
+}
+
 func TestTransactionSigHash(t *testing.T) {
 	var homestead HomesteadSigner
 	if homestead.Hash(emptyTx) != common.HexToHash("c775b99e7ad12f50d819fcd602390467e28141316969f4b57f0626f74fe3b386") {
@@ -405,6 +443,161 @@ func TestTransactionTimeSort(t *testing.T) {
 	}
 }
 
+// Tests that mining transactions are prioritized by the reward they pay, and by
+// their difficulty if the rewards are equal, regardless of their gas price.
+func TestMiningTransactionSort(t *testing.T) {
+	signer := LatestSignerForChainID(big.NewInt(1))
+
+	var (
+		keys   = make([]*ecdsa.PrivateKey, 4)
+		groups = map[common.Address]Transactions{}
+	)
+	for i := range keys {
+		keys[i], _ = crypto.GenerateKey()
+	}
+	for i, mining := range []struct {
+		value, difficulty int64
+	}{
+		{1000, 1}, // lowest reward, seen first
+		{2000, 2}, // same gas, higher difficulty and reward
+		{4000, 2}, // same difficulty, higher reward per hash
+		{4000, 4}, // same reward, higher difficulty, seen last
+	} {
+		inner := newTestMiningTx(crypto.PubkeyToAddress(keys[i].PublicKey))
+		inner.Value = big.NewInt(mining.value)
+		inner.Difficulty = big.NewInt(mining.difficulty)
+
+		tx, err := SignNewTx(keys[i], signer, inner)
+		if err != nil {
+			t.Fatal(err)
+		}
+		tx.time = time.Unix(int64(i), 0)
+		groups[inner.From] = Transactions{tx}
+	}
+	txset := NewTransactionsByPriceAndNonce(signer, groups, big.NewInt(1))
+
+	var have [][2]int64
+	for tx := txset.Peek(); tx != nil; tx = txset.Peek() {
+		have = append(have, [2]int64{tx.Value().Int64(), tx.Difficulty().Int64()})
+		txset.Shift()
+	}
+	want := [][2]int64{{4000, 4}, {4000, 2}, {2000, 2}, {1000, 1}}
+	if !reflect.DeepEqual(have, want) {
+		t.Errorf("mining transaction order mismatch: have %v, want %v", have, want)
+	}
+}
+
+// Tests that the strict binary decoding rejects mining transactions whose From
+// field was not signed by the claimed sender.
+func TestMiningTxUnmarshalBinaryStrict(t *testing.T) {
+	key, addr := defaultTestKey()
+	forger, _ := crypto.GenerateKey()
+	signer := NewLondonSigner(big.NewInt(1))
+
+	valid, err := SignNewTx(key, signer, newTestMiningTx(addr))
+	if err != nil {
+		t.Fatal(err)
+	}
+	forged, err := SignNewTx(forger, signer, newTestMiningTx(addr))
+	if err != nil {
+		t.Fatal(err)
+	}
+	unsigned := NewTx(newTestMiningTx(addr))
+
+	tests := []struct {
+		tx  *Transaction
+		err error
+	}{
+		{valid, nil},
+		{unsigned, nil},
+		{forged, ErrMiningTxFromMismatch},
+	}
+	for i, test := range tests {
+		blob, err := test.tx.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		// The lenient decoding accepts every transaction.
+		var lenient Transaction
+		if err := lenient.UnmarshalBinary(blob); err != nil {
+			t.Fatalf("test %d: lenient decoding failed: %v", i, err)
+		}
+		var strict Transaction
+		if err := strict.UnmarshalBinaryStrict(blob, signer); !errors.Is(err, test.err) {
+			t.Errorf("test %d: error mismatch: have %v, want %v", i, err, test.err)
+			continue
+		}
+		if test.err == nil && strict.Hash() != test.tx.Hash() {
+			t.Errorf("test %d: hash mismatch: have %x, want %x", i, strict.Hash(), test.tx.Hash())
+		}
+	}
+}
+
+// Tests that mining transactions decoded from JSON reject access lists instead
+// of silently dropping them.
+func TestMiningTxJSONAccessList(t *testing.T) {
+	key, addr := defaultTestKey()
+	tx, err := SignNewTx(key, NewLondonSigner(big.NewInt(1)), newTestMiningTx(addr))
+	if err != nil {
+		t.Fatal(err)
+	}
+	blob, err := tx.MarshalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	withAccessList := func(list string) []byte {
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(blob, &fields); err != nil {
+			t.Fatal(err)
+		}
+		fields["accessList"] = json.RawMessage(list)
+		enc, err := json.Marshal(fields)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return enc
+	}
+	tests := []struct {
+		json []byte
+		err  error
+	}{
+		{blob, nil},
+		{withAccessList(`[]`), nil},
+		{withAccessList(`[{"address":"0x0000000000000000000000000000000000000001","storageKeys":[]}]`), ErrMiningTxAccessList},
+	}
+	for i, test := range tests {
+		var decoded Transaction
+		if err := decoded.UnmarshalJSON(test.json); err != test.err {
+			t.Errorf("test %d: error mismatch: have %v, want %v", i, err, test.err)
+			continue
+		}
+		if test.err == nil && decoded.Hash() != tx.Hash() {
+			t.Errorf("test %d: hash mismatch: have %x, want %x", i, decoded.Hash(), tx.Hash())
+		}
+	}
+}
+
+// Tests that mining transactions are gas free: a zero fee cap doesn't underflow
+// into a negative tip whatever the base fee is.
+func TestMiningTxEffectiveGasTip(t *testing.T) {
+	tx := NewTx(newTestMiningTx(common.Address{}))
+	for _, baseFee := range []*big.Int{nil, big.NewInt(0), big.NewInt(1000000000)} {
+		tip, err := tx.EffectiveGasTip(baseFee)
+		if err != nil {
+			t.Errorf("baseFee %v: unexpected error: %v", baseFee, err)
+		}
+		if tip.Sign() != 0 {
+			t.Errorf("baseFee %v: tip mismatch: have %v, want 0", baseFee, tip)
+		}
+		if _, err := NewTxWithMinerFee(tx, baseFee); err != nil {
+			t.Errorf("baseFee %v: failed to wrap mining transaction: %v", baseFee, err)
+		}
+	}
+	if cost := tx.Cost(); cost.Sign() != 0 {
+		t.Errorf("cost mismatch: have %v, want 0", cost)
+	}
+}
+
 // TestTransactionCoding tests serializing/de-serializing to/from rlp and JSON.
 func TestTransactionCoding(t *testing.T) {
 	key, err := crypto.GenerateKey()
@@ -113,6 +113,17 @@ web3._extend({
 			call: 'ethash_submitHashrate',
 			params: 2,
 		}),
+		new web3._extend.Method({
+			name: 'getRewardSplit',
+			call: 'ethash_getRewardSplit',
+			params: 0,
+		}),
+		new web3._extend.Method({
+			name: 'getForkEra',
+			call: 'ethash_getForkEra',
+			params: 2,
+			inputFormatter: [web3._extend.utils.fromDecimal, web3._extend.utils.fromDecimal],
+		}),
 	]
 });
 `
@@ -680,6 +691,11 @@ web3._extend({
 			name: 'getHashrate',
 			call: 'miner_getHashrate'
 		}),
+		new web3._extend.Method({
+			name: 'benchmark',
+			call: 'miner_benchmark',
+			params: 2,
+		}),
 	],
 	properties: []
 });
@@ -923,10 +923,8 @@ func (w *worker) commitTransactions(env *environment, txs *types.TransactionsByP
 				continue
 			}
 			// skip old mining transaction have different mining reward, not match this period
-			subsidy := w.engine.TransactionMiningSubsidy(w.chainConfig, env.header.Number)
-			value := new(big.Int).Mul(subsidy, tx.Difficulty())
-			if tx.Value().Cmp(value) != 0 {
-				log.Trace("Ignoring mining transaction, not match subsidy period", "hash", tx.Hash(), "tx value", tx.Value(), "subsidy", value)
+			if err := misc.VerifyMiningTxValue(w.chainConfig, w.engine.TransactionMiningSubsidy, env.header.Number, tx); err != nil {
+				log.Trace("Ignoring mining transaction, not match subsidy period", "hash", tx.Hash(), "err", err)
 				txs.Shift()
 				continue
 			}
@@ -427,6 +427,11 @@ type ChainConfig struct {
 	CanxiumBlock        *big.Int `json:"canxiumBlock,omitempty"`        // Canxium blockchain block
 	HydroBlock          *big.Int `json:"hydroBlock,omitempty"`          // First hardfork for canxium chain, to reset first year block reward
 
+	// MiningAlgorithmBlock is the first block verifying mining transactions with
+	// the mining algorithm they declare. Before it, every mining transaction is
+	// verified as ethash regardless of its algorithm.
+	MiningAlgorithmBlock *big.Int `json:"miningAlgorithmBlock,omitempty"`
+
 	// Fork scheduling was switched from blocks to timestamps here
 
 	ShanghaiTime *uint64 `json:"shanghaiTime,omitempty"` // Shanghai switch time (nil = no fork, 0 = already on shanghai)
@@ -449,6 +454,68 @@ type ChainConfig struct {
 	// Canxium foundation wallet, should change to multi sig wallet in the future fork
 	Foundation     common.Address `json:"foundation,omitempty"`
 	MiningContract common.Address `json:"miningContract,omitempty"`
+
+	// Deployer of the mining contract, used to derive MiningContract on startup
+	// if it isn't configured explicitly.
+	MiningContractDeployer *MiningContractDeployerConfig `json:"miningContractDeployer,omitempty"`
+
+	// Offline mining reward schedule per mining algorithm, activated at the given
+	// blocks. Algorithms not listed here, or blocks before the activation, use the
+	// default ethash schedule of the consensus engine.
+	MiningRewards map[uint8]*MiningRewardConfig `json:"miningRewards,omitempty"`
+
+	// Block reward split between the miner and the foundation wallet, activated
+	// at the given blocks. Blocks before the first split use the default split of
+	// the consensus engine.
+	RewardSplits []RewardSplitConfig `json:"rewardSplits,omitempty"`
+
+	// Offline mining transactions built against the previous subsidy period are
+	// still accepted for MiningRewardGraceWindow blocks after a subsidy reduction,
+	// starting from MiningRewardGraceBlock.
+	MiningRewardGraceBlock  *big.Int `json:"miningRewardGraceBlock,omitempty"`
+	MiningRewardGraceWindow uint64   `json:"miningRewardGraceWindow,omitempty"`
+
+	// MiningRewardWaiverBlock is the first block accepting offline mining
+	// transactions with a zero value, so test networks can exercise propagation
+	// and verification without minting. It must never be enabled on a public
+	// network.
+	MiningRewardWaiverBlock *big.Int `json:"miningRewardWaiverBlock,omitempty"`
+}
+
+// MiningContractDeployerConfig is the account and account nonce the mining
+// contract is created with.
+type MiningContractDeployerConfig struct {
+	Address common.Address `json:"address"`
+	Nonce   uint64         `json:"nonce"`
+}
+
+// RewardSplitConfig is the share of the block reward paid to the foundation
+// wallet from a given block onwards, the rest goes to the miner.
+type RewardSplitConfig struct {
+	Block             *big.Int `json:"block"`             // First block the split applies to
+	FoundationPercent uint64   `json:"foundationPercent"` // Percentage of the block reward paid to the foundation
+}
+
+// ethashAlgorithm is the mining algorithm identifier of ethash, mirroring
+// types.EthashAlgorithm which can't be imported here.
+const ethashAlgorithm = 1
+
+// MiningRewardConfig is the offline mining reward schedule of a mining algorithm
+// from a given block onwards.
+type MiningRewardConfig struct {
+	Block         *big.Int `json:"block"`         // First block the schedule applies to
+	MaxReward     *big.Int `json:"maxReward"`     // Reward in wei per difficulty hash in the first reduction period
+	RewardPerHash *big.Int `json:"rewardPerHash"` // Reward in wei per difficulty hash once the reduction periods are over
+}
+
+// MiningReward returns the offline mining reward schedule of the given mining
+// algorithm active at the given block, or nil if the algorithm has no specific
+// schedule configured or it isn't activated yet.
+func (c *ChainConfig) MiningReward(algorithm uint8, num *big.Int) *MiningRewardConfig {
+	if reward := c.MiningRewards[algorithm]; reward != nil && isBlockForked(reward.Block, num) {
+		return reward
+	}
+	return nil
 }
 
 // EthashConfig is the consensus engine configs for proof-of-work based sealing.
@@ -548,6 +615,18 @@ func (c *ChainConfig) Description() string {
 		banner += fmt.Sprintf(" - Hydro Fork:                  #%-8v \n", c.HydroBlock)
 	}
 
+	if c.MiningRewardGraceBlock != nil {
+		banner += fmt.Sprintf(" - Mining Reward Grace:         #%-8v \n", c.MiningRewardGraceBlock)
+	}
+
+	if c.MiningAlgorithmBlock != nil {
+		banner += fmt.Sprintf(" - Mining Algorithm:            #%-8v \n", c.MiningAlgorithmBlock)
+	}
+
+	if c.MiningRewardWaiverBlock != nil {
+		banner += fmt.Sprintf(" - Mining Reward Waiver:        #%-8v \n", c.MiningRewardWaiverBlock)
+	}
+
 	// Add a special section for the merge as it's non-obvious
 	if c.TerminalTotalDifficulty == nil {
 		banner += "The Merge is not yet available for this network!\n"
@@ -684,6 +763,48 @@ func (c *ChainConfig) IsHydro(num *big.Int) bool {
 	return isBlockForked(c.HydroBlock, num)
 }
 
+// IsMiningAlgorithm returns whether num is either equal to the mining algorithm
+// fork block or greater.
+func (c *ChainConfig) IsMiningAlgorithm(num *big.Int) bool {
+	return isBlockForked(c.MiningAlgorithmBlock, num)
+}
+
+// IsMiningRewardGrace returns whether num is either equal to the mining reward
+// grace fork block or greater.
+func (c *ChainConfig) IsMiningRewardGrace(num *big.Int) bool {
+	return isBlockForked(c.MiningRewardGraceBlock, num)
+}
+
+// IsMiningRewardWaiver returns whether num is either equal to the mining reward
+// waiver fork block or greater.
+func (c *ChainConfig) IsMiningRewardWaiver(num *big.Int) bool {
+	return isBlockForked(c.MiningRewardWaiverBlock, num)
+}
+
+// RewardSplit returns the configured reward split active at the given block, or
+// nil if no split has been activated yet.
+func (c *ChainConfig) RewardSplit(num *big.Int) *RewardSplitConfig {
+	var split *RewardSplitConfig
+	for i := range c.RewardSplits {
+		if isBlockForked(c.RewardSplits[i].Block, num) {
+			split = &c.RewardSplits[i]
+		}
+	}
+	return split
+}
+
+// activeRewardSplits returns the configured reward splits activated at or
+// before the given block.
+func (c *ChainConfig) activeRewardSplits(num *big.Int) []RewardSplitConfig {
+	var splits []RewardSplitConfig
+	for _, split := range c.RewardSplits {
+		if isBlockForked(split.Block, num) {
+			splits = append(splits, split)
+		}
+	}
+	return splits
+}
+
 // CheckCompatible checks whether scheduled fork transitions have been imported
 // with a mismatching chain configuration.
 func (c *ChainConfig) CheckCompatible(newcfg *ChainConfig, height uint64, time uint64) *ConfigCompatError {
@@ -773,6 +894,56 @@ func (c *ChainConfig) CheckConfigForkOrder() error {
 			lastFork = cur
 		}
 	}
+	// Reward splits must be scheduled in order and can't pay out more than the
+	// whole block reward
+	var lastSplit *big.Int
+	for i, split := range c.RewardSplits {
+		if split.Block == nil {
+			return fmt.Errorf("reward split %d has no activation block", i)
+		}
+		if lastSplit != nil && lastSplit.Cmp(split.Block) >= 0 {
+			return fmt.Errorf("unsupported reward split ordering: split %d enabled at block %v, but split %d enabled at block %v",
+				i-1, lastSplit, i, split.Block)
+		}
+		if split.FoundationPercent > 100 {
+			return fmt.Errorf("invalid reward split %d: foundation percent %d above 100", i, split.FoundationPercent)
+		}
+		lastSplit = split.Block
+	}
+	// The mining reward grace window must cover at least one block and can't
+	// reach back further than one subsidy reduction period
+	if c.MiningRewardGraceBlock != nil {
+		if c.MiningRewardGraceWindow == 0 || c.MiningRewardGraceWindow > CanxiumMiningReduceBlock.Uint64() {
+			return fmt.Errorf("invalid mining reward grace window %d, want 1 to %d", c.MiningRewardGraceWindow, CanxiumMiningReduceBlock)
+		}
+	}
+	// The mining reward waiver is for test networks only
+	if c.MiningRewardWaiverBlock != nil && c.ChainID != nil && c.ChainID.Cmp(MainnetChainConfig.ChainID) == 0 {
+		return fmt.Errorf("mining reward waiver enabled at block %v on mainnet", c.MiningRewardWaiverBlock)
+	}
+	// Mining reward schedules must be activated at a block and can't fall back
+	// to the default schedule through missing rewards
+	for algorithm := 0; algorithm <= 0xff; algorithm++ {
+		reward := c.MiningRewards[uint8(algorithm)]
+		if reward == nil {
+			continue
+		}
+		if reward.Block == nil {
+			return fmt.Errorf("mining reward of algorithm %d has no activation block", algorithm)
+		}
+		// Mining transactions are verified as ethash before the mining algorithm
+		// fork, so other algorithms can't have their own schedule before it
+		if algorithm != ethashAlgorithm && (c.MiningAlgorithmBlock == nil || c.MiningAlgorithmBlock.Cmp(reward.Block) > 0) {
+			return fmt.Errorf("unsupported mining reward ordering: algorithm %d enabled at block %v, but mining algorithm fork enabled at block %v",
+				algorithm, reward.Block, c.MiningAlgorithmBlock)
+		}
+		if reward.MaxReward == nil || reward.MaxReward.Sign() <= 0 {
+			return fmt.Errorf("invalid mining reward of algorithm %d: max reward %v", algorithm, reward.MaxReward)
+		}
+		if reward.RewardPerHash == nil || reward.RewardPerHash.Sign() <= 0 {
+			return fmt.Errorf("invalid mining reward of algorithm %d: reward per hash %v", algorithm, reward.RewardPerHash)
+		}
+	}
 	return nil
 }
 
@@ -841,9 +1012,62 @@ func (c *ChainConfig) checkCompatible(newcfg *ChainConfig, headNumber *big.Int,
 	if isForkTimestampIncompatible(c.PragueTime, newcfg.PragueTime, headTimestamp) {
 		return newTimestampCompatError("Prague fork timestamp", c.PragueTime, newcfg.PragueTime)
 	}
+	if isForkBlockIncompatible(c.MiningRewardGraceBlock, newcfg.MiningRewardGraceBlock, headNumber) {
+		return newBlockCompatError("Mining reward grace fork block", c.MiningRewardGraceBlock, newcfg.MiningRewardGraceBlock)
+	}
+	if c.IsMiningRewardGrace(headNumber) && c.MiningRewardGraceWindow != newcfg.MiningRewardGraceWindow {
+		return newBlockCompatError("Mining reward grace window", c.MiningRewardGraceBlock, newcfg.MiningRewardGraceBlock)
+	}
+	if isForkBlockIncompatible(c.MiningRewardWaiverBlock, newcfg.MiningRewardWaiverBlock, headNumber) {
+		return newBlockCompatError("Mining reward waiver fork block", c.MiningRewardWaiverBlock, newcfg.MiningRewardWaiverBlock)
+	}
+	// Reward splits already active at the head can't be added, moved or changed
+	storedSplits, newSplits := c.activeRewardSplits(headNumber), newcfg.activeRewardSplits(headNumber)
+	for i := 0; i < len(storedSplits) || i < len(newSplits); i++ {
+		var stored, next RewardSplitConfig
+		if i < len(storedSplits) {
+			stored = storedSplits[i]
+		}
+		if i < len(newSplits) {
+			next = newSplits[i]
+		}
+		if !configBlockEqual(stored.Block, next.Block) || stored.FoundationPercent != next.FoundationPercent {
+			return newBlockCompatError("Reward split", stored.Block, next.Block)
+		}
+	}
+	if isForkBlockIncompatible(c.MiningAlgorithmBlock, newcfg.MiningAlgorithmBlock, headNumber) {
+		return newBlockCompatError("Mining algorithm fork block", c.MiningAlgorithmBlock, newcfg.MiningAlgorithmBlock)
+	}
+	for algorithm := 0; algorithm <= 0xff; algorithm++ {
+		stored, next := c.MiningRewards[uint8(algorithm)], newcfg.MiningRewards[uint8(algorithm)]
+		if isMiningRewardIncompatible(stored, next, headNumber) {
+			return newBlockCompatError(fmt.Sprintf("Mining reward of algorithm %d", algorithm), stored.activation(), next.activation())
+		}
+	}
 	return nil
 }
 
+// activation returns the first block the mining reward schedule applies to, or
+// nil if there is no schedule.
+func (r *MiningRewardConfig) activation() *big.Int {
+	if r == nil {
+		return nil
+	}
+	return r.Block
+}
+
+// isMiningRewardIncompatible returns true if the mining reward schedule s1 can't
+// be replaced with s2 because head is already past the activation of either.
+func isMiningRewardIncompatible(s1, s2 *MiningRewardConfig, head *big.Int) bool {
+	if isForkBlockIncompatible(s1.activation(), s2.activation(), head) {
+		return true
+	}
+	if !isBlockForked(s1.activation(), head) {
+		return false
+	}
+	return !configBlockEqual(s1.MaxReward, s2.MaxReward) || !configBlockEqual(s1.RewardPerHash, s2.RewardPerHash)
+}
+
 // BaseFeeChangeDenominator bounds the amount the base fee can change between blocks.
 func (c *ChainConfig) BaseFeeChangeDenominator() uint64 {
 	return DefaultBaseFeeChangeDenominator
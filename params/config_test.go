@@ -19,6 +19,7 @@ package params
 import (
 	"math/big"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 
@@ -109,6 +110,140 @@ func TestCheckCompatible(t *testing.T) {
 				RewindToTime: 9,
 			},
 		},
+		{
+			stored:    &ChainConfig{MiningRewards: map[uint8]*MiningRewardConfig{2: {Block: big.NewInt(10), MaxReward: big.NewInt(1000), RewardPerHash: big.NewInt(100)}}},
+			new:       &ChainConfig{MiningRewards: map[uint8]*MiningRewardConfig{2: {Block: big.NewInt(20), MaxReward: big.NewInt(1000), RewardPerHash: big.NewInt(100)}}},
+			headBlock: 9,
+			wantErr:   nil,
+		},
+		{
+			stored:    &ChainConfig{MiningRewardGraceBlock: big.NewInt(10), MiningRewardGraceWindow: 100},
+			new:       &ChainConfig{MiningRewardGraceBlock: big.NewInt(20), MiningRewardGraceWindow: 50},
+			headBlock: 9,
+			wantErr:   nil,
+		},
+		{
+			stored:    &ChainConfig{MiningRewardGraceBlock: big.NewInt(10), MiningRewardGraceWindow: 100},
+			new:       &ChainConfig{MiningRewardGraceBlock: big.NewInt(20), MiningRewardGraceWindow: 100},
+			headBlock: 25,
+			wantErr: &ConfigCompatError{
+				What:          "Mining reward grace fork block",
+				StoredBlock:   big.NewInt(10),
+				NewBlock:      big.NewInt(20),
+				RewindToBlock: 9,
+			},
+		},
+		{
+			stored:    &ChainConfig{MiningRewardGraceBlock: big.NewInt(10), MiningRewardGraceWindow: 100},
+			new:       &ChainConfig{MiningRewardGraceBlock: big.NewInt(10), MiningRewardGraceWindow: 50},
+			headBlock: 25,
+			wantErr: &ConfigCompatError{
+				What:          "Mining reward grace window",
+				StoredBlock:   big.NewInt(10),
+				NewBlock:      big.NewInt(10),
+				RewindToBlock: 9,
+			},
+		},
+		{
+			stored:    &ChainConfig{},
+			new:       &ChainConfig{MiningRewardWaiverBlock: big.NewInt(30)},
+			headBlock: 25,
+			wantErr:   nil,
+		},
+		{
+			stored:    &ChainConfig{},
+			new:       &ChainConfig{MiningRewardWaiverBlock: big.NewInt(20)},
+			headBlock: 25,
+			wantErr: &ConfigCompatError{
+				What:          "Mining reward waiver fork block",
+				StoredBlock:   nil,
+				NewBlock:      big.NewInt(20),
+				RewindToBlock: 19,
+			},
+		},
+		{
+			stored:    &ChainConfig{MiningRewardWaiverBlock: big.NewInt(10)},
+			new:       &ChainConfig{},
+			headBlock: 25,
+			wantErr: &ConfigCompatError{
+				What:          "Mining reward waiver fork block",
+				StoredBlock:   big.NewInt(10),
+				NewBlock:      nil,
+				RewindToBlock: 9,
+			},
+		},
+		{
+			stored:    &ChainConfig{RewardSplits: []RewardSplitConfig{{Block: big.NewInt(10), FoundationPercent: 10}}},
+			new:       &ChainConfig{RewardSplits: []RewardSplitConfig{{Block: big.NewInt(10), FoundationPercent: 10}, {Block: big.NewInt(30), FoundationPercent: 2}}},
+			headBlock: 25,
+			wantErr:   nil,
+		},
+		{
+			stored:    &ChainConfig{},
+			new:       &ChainConfig{RewardSplits: []RewardSplitConfig{{Block: big.NewInt(10), FoundationPercent: 10}}},
+			headBlock: 25,
+			wantErr: &ConfigCompatError{
+				What:          "Reward split",
+				StoredBlock:   nil,
+				NewBlock:      big.NewInt(10),
+				RewindToBlock: 9,
+			},
+		},
+		{
+			stored:    &ChainConfig{RewardSplits: []RewardSplitConfig{{Block: big.NewInt(10), FoundationPercent: 10}}},
+			new:       &ChainConfig{RewardSplits: []RewardSplitConfig{{Block: big.NewInt(20), FoundationPercent: 10}}},
+			headBlock: 25,
+			wantErr: &ConfigCompatError{
+				What:          "Reward split",
+				StoredBlock:   big.NewInt(10),
+				NewBlock:      big.NewInt(20),
+				RewindToBlock: 9,
+			},
+		},
+		{
+			stored:    &ChainConfig{RewardSplits: []RewardSplitConfig{{Block: big.NewInt(10), FoundationPercent: 10}}},
+			new:       &ChainConfig{RewardSplits: []RewardSplitConfig{{Block: big.NewInt(10), FoundationPercent: 5}}},
+			headBlock: 25,
+			wantErr: &ConfigCompatError{
+				What:          "Reward split",
+				StoredBlock:   big.NewInt(10),
+				NewBlock:      big.NewInt(10),
+				RewindToBlock: 9,
+			},
+		},
+		{
+			stored:    &ChainConfig{RewardSplits: []RewardSplitConfig{{Block: big.NewInt(10), FoundationPercent: 10}}},
+			new:       &ChainConfig{},
+			headBlock: 25,
+			wantErr: &ConfigCompatError{
+				What:          "Reward split",
+				StoredBlock:   big.NewInt(10),
+				NewBlock:      nil,
+				RewindToBlock: 9,
+			},
+		},
+		{
+			stored:    &ChainConfig{},
+			new:       &ChainConfig{MiningRewards: map[uint8]*MiningRewardConfig{2: {Block: big.NewInt(10), MaxReward: big.NewInt(1000), RewardPerHash: big.NewInt(100)}}},
+			headBlock: 25,
+			wantErr: &ConfigCompatError{
+				What:          "Mining reward of algorithm 2",
+				StoredBlock:   nil,
+				NewBlock:      big.NewInt(10),
+				RewindToBlock: 9,
+			},
+		},
+		{
+			stored:    &ChainConfig{MiningRewards: map[uint8]*MiningRewardConfig{2: {Block: big.NewInt(10), MaxReward: big.NewInt(1000), RewardPerHash: big.NewInt(100)}}},
+			new:       &ChainConfig{MiningRewards: map[uint8]*MiningRewardConfig{2: {Block: big.NewInt(10), MaxReward: big.NewInt(2000), RewardPerHash: big.NewInt(100)}}},
+			headBlock: 25,
+			wantErr: &ConfigCompatError{
+				What:          "Mining reward of algorithm 2",
+				StoredBlock:   big.NewInt(10),
+				NewBlock:      big.NewInt(10),
+				RewindToBlock: 9,
+			},
+		},
 	}
 
 	for _, test := range tests {
@@ -136,3 +271,49 @@ func TestConfigRules(t *testing.T) {
 		t.Errorf("expected %v to be shanghai", stamp)
 	}
 }
+
+func TestMiningRewardGraceConfig(t *testing.T) {
+	reduce := CanxiumMiningReduceBlock.Uint64()
+	tests := []struct {
+		block  *big.Int
+		window uint64
+		valid  bool
+	}{
+		{nil, 0, true},
+		{big.NewInt(10), 0, false},
+		{big.NewInt(10), 1, true},
+		{big.NewInt(10), reduce, true},
+		{big.NewInt(10), reduce + 1, false},
+	}
+	for i, test := range tests {
+		config := &ChainConfig{MiningRewardGraceBlock: test.block, MiningRewardGraceWindow: test.window}
+		if err := config.CheckConfigForkOrder(); (err == nil) != test.valid {
+			t.Errorf("test %d: validity mismatch: have %v, want valid %v", i, err, test.valid)
+		}
+		if test.block != nil && !strings.Contains(config.Description(), "Mining Reward Grace") {
+			t.Errorf("test %d: grace fork missing from description", i)
+		}
+	}
+}
+
+func TestMiningRewardWaiverConfig(t *testing.T) {
+	tests := []struct {
+		chainID *big.Int
+		block   *big.Int
+		valid   bool
+	}{
+		{MainnetChainConfig.ChainID, nil, true},
+		{MainnetChainConfig.ChainID, big.NewInt(0), false},
+		{MainnetChainConfig.ChainID, big.NewInt(10), false},
+		{big.NewInt(1337), big.NewInt(0), true},
+	}
+	for i, test := range tests {
+		config := &ChainConfig{ChainID: test.chainID, MiningRewardWaiverBlock: test.block}
+		if err := config.CheckConfigForkOrder(); (err == nil) != test.valid {
+			t.Errorf("test %d: validity mismatch: have %v, want valid %v", i, err, test.valid)
+		}
+		if test.block != nil && !strings.Contains(config.Description(), "Mining Reward Waiver") {
+			t.Errorf("test %d: waiver fork missing from description", i)
+		}
+	}
+}